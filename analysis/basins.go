@@ -0,0 +1,174 @@
+package analysis
+
+import (
+    "math"
+    "sort"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// Basin is one of the attractors found at a single r along with
+// the share of the grid's initial conditions that end up on it:
+// its period (0 for chaotic or unresolved), the cycle points in
+// increasing order (empty when chaotic), the interval [Lo, Hi] the
+// long-time orbits cover and their mean
+type Basin struct {
+    Period   int
+    Cycle    []float64
+    Lo, Hi   float64
+    Mean     float64
+    Cells    int
+    Fraction float64
+}
+
+// BasinMap is the result of Multistability: the attractors at every
+// r in increasing order of Mean, and for every (r, x0) cell (indexed
+// ir*NX + ix) which of them it ends up on (-1 when the orbit blew
+// up or landed exactly on an unstable cycle) and its last iterate
+type BasinMap struct {
+    Basins [][]Basin
+    Labels []int
+    Last   []float64
+}
+
+// long-time state of one cell
+type cell_state struct {
+    period int
+    cycle  []float64
+    lo, hi float64
+    mean   float64
+}
+
+// Multistability looks for coexisting attractors at every r on the
+// grid. Each x0 is iterated for trans steps and the next keep
+// iterates summarise where it settled: cycles of period up to pmax
+// are the same attractor when their points agree within tol, and
+// orbits with no period are the same when more than half of the
+// shorter of their intervals overlaps (coexisting chaotic
+// attractors of a one-dimensional map sit on disjoint intervals).
+// Orbits still settling next to a bifurcation or the edge of a
+// window look like extra attractors of another period, so an r
+// whose attractors don't all share a period is settled again for
+// 10*trans
+func (g *Grid) Multistability(trans, keep, pmax int, tol float64) BasinMap {
+    bm := BasinMap{
+        Basins: make([][]Basin, g.NR),
+        Labels: make([]int, g.NR*g.NX),
+        Last:   make([]float64, g.NR*g.NX),
+    }
+    g.Sweep(g.NR, func(ir int) {
+        states := make([]cell_state, g.NX)
+        for _, t := range []int{trans, 10 * trans} {
+            for ix := range states {
+                xs := g.Orbit(ir, ix, t, keep)
+                bm.Last[ir*g.NX+ix] = xs[len(xs)-1]
+                states[ix] = summarise(g.Map, g.R(ir), xs, pmax)
+            }
+            bm.Basins[ir] = cluster(states, bm.Labels[ir*g.NX:(ir+1)*g.NX], tol)
+            if one_period(bm.Basins[ir]) {
+                break
+            }
+        }
+    })
+    return bm
+}
+
+// whether every attractor has the same period
+func one_period(basins []Basin) bool {
+    for _, b := range basins {
+        if b.Period != basins[0].Period {
+            return false
+        }
+    }
+    return true
+}
+
+// the period (with its cycle) and extent of a settled orbit; a
+// blown-up orbit gets a NaN mean, as does one sitting on an
+// unstable cycle (x0 = 0 for the cubic map is a fixed point, but
+// not an attractor)
+func summarise(m maps.Map1D, r float64, xs []float64, pmax int) cell_state {
+    s := cell_state{lo: math.Inf(1), hi: math.Inf(-1)}
+    for _, x := range xs {
+        if math.IsNaN(x) || math.IsInf(x, 0) {
+            s.mean = math.NaN()
+            return s
+        }
+        s.lo = math.Min(s.lo, x)
+        s.hi = math.Max(s.hi, x)
+        s.mean += x
+    }
+    s.mean /= float64(len(xs))
+    if s.period = DetectPeriod(xs, pmax); s.period > 0 {
+        if math.Abs(Multiplier(m, r, xs[len(xs)-1], s.period)) > 1+1e-6 {
+            s.mean = math.NaN()
+            return s
+        }
+        s.cycle = append([]float64(nil), xs[len(xs)-s.period:]...)
+        sort.Float64s(s.cycle)
+    }
+    return s
+}
+
+// whether two settled orbits are on the same attractor
+func same_attractor(a, b cell_state, tol float64) bool {
+    if a.period != b.period {
+        return false
+    }
+    if a.period > 0 {
+        for i := range a.cycle {
+            if math.Abs(a.cycle[i]-b.cycle[i]) > tol {
+                return false
+            }
+        }
+        return true
+    }
+    overlap := math.Min(a.hi, b.hi) - math.Max(a.lo, b.lo)
+    return overlap > 0.5*math.Min(a.hi-a.lo, b.hi-b.lo)
+}
+
+// group the cells at one r by attractor, writing each cell's
+// attractor into labels and returning the attractors sorted by mean
+func cluster(states []cell_state, labels []int, tol float64) []Basin {
+    var reps []cell_state
+    var basins []Basin
+    for ix, s := range states {
+        labels[ix] = -1
+        if math.IsNaN(s.mean) {
+            continue
+        }
+        for k := range reps {
+            if same_attractor(reps[k], s, tol) {
+                labels[ix] = k
+                break
+            }
+        }
+        if labels[ix] < 0 {
+            labels[ix] = len(reps)
+            reps = append(reps, s)
+            basins = append(basins, Basin{Period: s.period, Cycle: s.cycle, Lo: s.lo, Hi: s.hi, Mean: s.mean})
+        }
+        basins[labels[ix]].Cells++
+    }
+
+    // number the attractors from the bottom up so the labels follow
+    // the branches of the diagram from one r to the next
+    order := make([]int, len(basins))
+    for k := range order {
+        order[k] = k
+    }
+    sort.SliceStable(order, func(i, j int) bool { return basins[order[i]].Mean < basins[order[j]].Mean })
+    rank := make([]int, len(basins))
+    sorted := make([]Basin, len(basins))
+    for k, o := range order {
+        rank[o] = k
+        sorted[k] = basins[o]
+        sorted[k].Fraction = float64(basins[o].Cells) / float64(len(states))
+    }
+    for ix, l := range labels {
+        if l >= 0 {
+            labels[ix] = rank[l]
+        }
+    }
+    return sorted
+}
@@ -0,0 +1,84 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// a grid with the one row r over [x_lo, x_hi)
+func one_row(m maps.Map1D, r, x_lo, x_hi float64) *Grid {
+    return &Grid{Map: m, RMin: r, RStep: 1, NR: 1,
+        XMin: x_lo, XStep: (x_hi - x_lo) / GridX, NX: GridX, Stats: &Stats{}}
+}
+
+// the cubic map is odd, so each attractor (the fixed points
+// +-sqrt(1 - 1/r) for 1 < r < 2) has a mirror image on the other
+// side of 0 that takes every x0 of the other sign, until they
+// merge in the crisis at r = 3 sqrt(3)/2 where f(c) reaches 1. x0 =
+// -1 and 0 land on the unstable fixed point at 0 and count for
+// neither
+func TestBasinsCubic(t *testing.T) {
+    for _, c := range []struct {
+        r      float64
+        period int
+    }{{1.5, 1}, {2.1, 2}, {2.25, 4}, {2.55, 0}} {
+        bm := one_row(maps.Cubic{}, c.r, -1, 1).Multistability(1000, 200, 64, 1e-6)
+        basins := bm.Basins[0]
+        if len(basins) != 2 {
+            t.Errorf("r = %v: %v attractors, want 2", c.r, len(basins))
+            continue
+        }
+        for _, b := range basins {
+            if b.Period != c.period || b.Cells != 49 || math.Abs(b.Fraction-0.49) > 1e-12 {
+                t.Errorf("r = %v: period %v with %v cells (fraction %v), want period %v with 49", c.r, b.Period, b.Cells, b.Fraction, c.period)
+            }
+        }
+        if fixed := math.Sqrt(1 - 1/c.r); c.period == 1 && math.Abs(basins[1].Cycle[0]-fixed) > 1e-9 {
+            t.Errorf("r = %v: fixed point %v, want %v", c.r, basins[1].Cycle, fixed)
+        }
+        if lo, hi := basins[0], basins[1]; math.Abs(lo.Lo+hi.Hi) > 1e-4 || math.Abs(lo.Hi+hi.Lo) > 1e-4 {
+            t.Errorf("r = %v: attractors on [%v, %v] and [%v, %v] aren't mirror images", c.r, lo.Lo, lo.Hi, hi.Lo, hi.Hi)
+        }
+        for ix, l := range bm.Labels {
+            x0 := -1 + 0.02*float64(ix)
+            want := 1
+            switch {
+            case ix == 0 || ix == GridX/2:
+                want = -1
+            case x0 < 0:
+                want = 0
+            }
+            if l != want {
+                t.Errorf("r = %v, x0 = %.2f: attractor %v, want %v", c.r, x0, l, want)
+            }
+        }
+    }
+
+    bm := one_row(maps.Cubic{}, 2.6, -1, 1).Multistability(1000, 200, 64, 1e-6)
+    if basins := bm.Basins[0]; len(basins) != 1 || basins[0].Cells != 98 {
+        t.Errorf("r = 2.6: %v attractors, want the merged one with 98 cells", len(basins))
+    }
+}
+
+// the logistic map has one attractor, here the 2-cycle
+// (r + 1 +- sqrt((r + 1)(r - 3)))/2r, taking every x0 but 0
+func TestBasinsLogistic(t *testing.T) {
+    r := 3.2
+    bm := one_row(maps.Logistic{}, r, 0, 1).Multistability(1000, 200, 64, 1e-6)
+    basins := bm.Basins[0]
+    if len(basins) != 1 {
+        t.Fatalf("%v attractors, want 1", len(basins))
+    }
+    b := basins[0]
+    if b.Period != 2 || math.Abs(b.Fraction-0.99) > 1e-12 {
+        t.Errorf("period %v with fraction %v, want 2 with 0.99", b.Period, b.Fraction)
+    }
+    root := math.Sqrt((r + 1) * (r - 3))
+    for i, want := range []float64{(r + 1 - root) / (2 * r), (r + 1 + root) / (2 * r)} {
+        if math.Abs(b.Cycle[i]-want) > 1e-9 {
+            t.Errorf("cycle point %v: %v, want %v", i, b.Cycle[i], want)
+        }
+    }
+}
@@ -0,0 +1,124 @@
+package analysis
+
+import (
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// Attractor is an attracting cycle: the cycle points in the order
+// they are visited and the multiplier (f^p)'(x*), the product of
+// f' around the cycle
+type Attractor struct {
+    Cycle []float64
+    Mult  float64
+}
+
+// FindAttractor numerically locates the attractor reached from x0:
+// settle for trans iterations, detect the period and polish the
+// cycle with Newton's method. Slow convergence near a bifurcation
+// gets a second, longer transient and then a direct search for a
+// marginally stable cycle. It reports whether a cycle of period at
+// most pmax was found
+func FindAttractor(m maps.Map1D, r, x0 float64, trans, pmax int) (Attractor, bool) {
+    for _, t := range []int{trans, 20 * trans} {
+        xs := maps.Gen(m, r, x0, t, 2*pmax)
+        p := DetectPeriod(xs, pmax)
+        if p == 0 {
+            continue
+        }
+        x, ok := FindCycle(m, r, xs[len(xs)-1], p)
+        if !ok || math.Abs(x-xs[len(xs)-1]) > 10*PeriodTol {
+            // Newton wandered off to some other (unstable) cycle
+            continue
+        }
+        cycle := maps.Gen(m, r, x, 0, p)
+        return Attractor{Cycle: cycle, Mult: Multiplier(m, r, x, p)}, true
+    }
+
+    // right at a bifurcation the approach is only algebraic, so
+    // look for a marginally stable cycle close to the orbit instead
+    x_end := maps.Gen(m, r, x0, 20*trans, 1)[0]
+    for p := 1; p <= pmax; p++ {
+        x, ok := FindCycle(m, r, x_end, p)
+        if ok && math.Abs(x-x_end) < 1e-2 && math.Abs(Multiplier(m, r, x, p)) <= 1+1e-6 {
+            return Attractor{Cycle: maps.Gen(m, r, x, 0, p), Mult: Multiplier(m, r, x, p)}, true
+        }
+    }
+    return Attractor{}, false
+}
+
+// Regular reports whether (r, x0) settles onto a regular
+// (non-chaotic) attractor, i.e. an attracting cycle can be found
+func Regular(m maps.Map1D, r, x0 float64, trans, pmax int) bool {
+    _, ok := FindAttractor(m, r, x0, trans, pmax)
+    return ok
+}
+
+// Nearest returns the cycle point closest to x
+func (a Attractor) Nearest(x float64) float64 {
+    best := a.Cycle[0]
+    for _, c := range a.Cycle[1:] {
+        if math.Abs(x-c) < math.Abs(x-best) {
+            best = c
+        }
+    }
+    return best
+}
+
+// Settle iterates long enough to land (close to) on the attractor
+func Settle(m maps.Map1D, r, x float64) float64 {
+    for i := 0; i < 5000; i++ {
+        x = m.F(r, x)
+    }
+    return x
+}
+
+// FindCycle uses Newton's method for a point of a p-cycle,
+// f^p(x) = x, starting from the guess x. It returns the cycle
+// point and whether Newton converged
+func FindCycle(m maps.Map1D, r, x float64, p int) (float64, bool) {
+    for it := 0; it < 100; it++ {
+        y, dy := x, 1.
+        for k := 0; k < p; k++ {
+            dy *= m.DF(r, y)
+            y = m.F(r, y)
+        }
+        if dy == 1 || math.IsNaN(y) {
+            return x, false
+        }
+        step := (y - x) / (dy - 1)
+        x -= step
+        if math.Abs(step) < 1e-14 {
+            return x, true
+        }
+    }
+    return x, false
+}
+
+// Multiplier is the product of f'(x) around the p-cycle through x
+func Multiplier(m maps.Map1D, r, x float64, p int) float64 {
+    mult := 1.
+    for k := 0; k < p; k++ {
+        mult *= m.DF(r, x)
+        x = m.F(r, x)
+    }
+    return mult
+}
+
+// PowerOrbit returns x0 and the next n iterates of f^k, stopping
+// early if the orbit blows up
+func PowerOrbit(m maps.Map1D, r, x0 float64, k, n int) []float64 {
+    xs := []float64{x0}
+    x := x0
+    for i := 0; i < n; i++ {
+        for j := 0; j < k; j++ {
+            x = m.F(r, x)
+        }
+        if math.IsNaN(x) || math.IsInf(x, 0) {
+            break
+        }
+        xs = append(xs, x)
+    }
+    return xs
+}
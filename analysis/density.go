@@ -0,0 +1,47 @@
+package analysis
+
+import (
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// number of r values spread across each pixel column of the
+// density raster, to smooth out the structure inside a column
+const raster_subcols = 4
+
+// Density is the histogram behind the density raster of the
+// Feigenbaum diagram: every one of width columns iterates r values
+// inside it, throws away the transient and bins the next keep
+// iterates by x into height rows. Counts are returned row by row
+// from x_hi down
+func Density(m maps.Map1D, x0, r_lo, r_hi, x_lo, x_hi float64, width, height, trans, keep int) []float64 {
+    counts := make([]float64, width*height)
+    dr := (r_hi - r_lo) / float64(width)
+    // columns only touch their own bins, so they can run in parallel
+    Pool(width, func(col int) {
+        for s := 0; s < raster_subcols; s++ {
+            r := r_lo + (float64(col)+(float64(s)+0.5)/raster_subcols)*dr
+            m := maps.Start(m, r, x0)
+            x := x0
+            for i := 0; i < trans; i++ {
+                x = m.F(r, x)
+            }
+            for i := 0; i < keep/raster_subcols; i++ {
+                x = m.F(r, x)
+                if math.IsNaN(x) || math.IsInf(x, 0) {
+                    break
+                }
+                row := int(math.Floor((x_hi - x) / (x_hi - x_lo) * float64(height)))
+                if row == height {
+                    // x == x_lo belongs on the bottom row
+                    row--
+                }
+                if row >= 0 && row < height {
+                    counts[row*width+col]++
+                }
+            }
+        }
+    })
+    return counts
+}
@@ -0,0 +1,136 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+    "sort"
+
+    "gonum.org/v1/gonum/stat/distuv"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// dimensions of the attractor at the onset of chaos, the same for
+// every map with a quadratic maximum (Grassberger 1981, Grassberger
+// and Procaccia 1983)
+const (
+    FeigenbaumD0 = 0.538
+    FeigenbaumD1 = 0.517
+    FeigenbaumD2 = 0.500
+)
+
+// Confidence is the level of the intervals given for fitted
+// dimensions
+const Confidence = 0.95
+
+// DimFit is a dimension read off a log-log plot: at each scale eps
+// (Eps, decreasing) a quantity Ys that grows like D ln(1/eps), the
+// straight line fitted to them and the slope D with its standard
+// error and Confidence interval [Lo, Hi]
+type DimFit struct {
+    Eps, Ys     []float64
+    Dim, Icept  float64
+    Err, Lo, Hi float64
+}
+
+// Dimensions holds the box-counting (D0), information (D1) and
+// correlation (D2) dimensions of a sample of an attractor
+type Dimensions struct {
+    Box, Info, Corr DimFit
+    Points          int
+}
+
+// Scales returns n scales from eps_hi down to eps_lo, evenly spaced
+// in log
+func Scales(eps_hi, eps_lo float64, n int) []float64 {
+    eps := make([]float64, n)
+    for i := range eps {
+        eps[i] = eps_hi * math.Pow(eps_lo/eps_hi, float64(i)/float64(n-1))
+    }
+    return eps
+}
+
+// AttractorDimensions samples the attractor of (r, x0) with n
+// iterates after trans and estimates its dimensions from how three
+// quantities grow with ln(1/eps) over the scales eps: the log of the
+// number of boxes of size eps the orbit visits (D0), the entropy
+// -sum p ln p of how often each box is visited (D1) and minus the
+// log of the fraction of pairs of points closer than eps (D2). Each
+// is the slope of a least squares line over every scale, so eps
+// should stay between the size of the attractor and the spacing of
+// the sample (the box counts saturate once the points can't fill
+// the boxes). It returns an error when the orbit blew up, fewer than
+// three scales were given or no pair of points is closer than the
+// smallest scale
+func AttractorDimensions(m maps.Map1D, r, x0 float64, trans, n int, eps []float64) (Dimensions, error) {
+    if len(eps) < 3 {
+        return Dimensions{}, fmt.Errorf("need at least three scales, got %v", len(eps))
+    }
+    xs := maps.Gen(m, r, x0, trans, n)
+    for _, x := range xs {
+        if math.IsNaN(x) || math.IsInf(x, 0) {
+            return Dimensions{}, fmt.Errorf("orbit of x0 = %v blew up at r = %v", x0, r)
+        }
+    }
+    sort.Float64s(xs)
+
+    d := Dimensions{Points: n}
+    boxes, info, corr := make([]float64, len(eps)), make([]float64, len(eps)), make([]float64, len(eps))
+    for k, e := range eps {
+        boxes[k], info[k] = box_counts(xs, e)
+        frac := pair_fraction(xs, e)
+        if frac == 0 {
+            return Dimensions{}, fmt.Errorf("no pair of the %v points is closer than %g", n, e)
+        }
+        corr[k] = -math.Log(frac)
+    }
+    d.Box = fit_dim(eps, boxes)
+    d.Info = fit_dim(eps, info)
+    d.Corr = fit_dim(eps, corr)
+    return d, nil
+}
+
+// ln of the number of boxes of size e that the sorted points xs
+// fall in, and the entropy of the fraction in each box
+func box_counts(xs []float64, e float64) (float64, float64) {
+    n := float64(len(xs))
+    boxes, entropy := 0, 0.
+    for i := 0; i < len(xs); {
+        box := math.Floor(xs[i] / e)
+        j := i + 1
+        for j < len(xs) && math.Floor(xs[j]/e) == box {
+            j++
+        }
+        p := float64(j-i) / n
+        boxes++
+        entropy -= p * math.Log(p)
+        i = j
+    }
+    return math.Log(float64(boxes)), entropy
+}
+
+// fraction of the pairs of sorted points xs less than e apart
+func pair_fraction(xs []float64, e float64) float64 {
+    pairs := int64(0)
+    j := 0
+    for i := range xs {
+        for xs[i]-xs[j] >= e {
+            j++
+        }
+        pairs += int64(i - j)
+    }
+    n := int64(len(xs))
+    return float64(pairs) / float64(n*(n-1)/2)
+}
+
+// the slope of ys against ln(1/eps), with its Confidence interval
+// from the t distribution
+func fit_dim(eps, ys []float64) DimFit {
+    ls := make([]float64, len(eps))
+    for i, e := range eps {
+        ls[i] = -math.Log(e)
+    }
+    slope, icept, err := FitLine(ls, ys)
+    t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(len(ls) - 2)}.Quantile(0.5 + Confidence/2)
+    return DimFit{Eps: eps, Ys: ys, Dim: slope, Icept: icept, Err: err, Lo: slope - t*err, Hi: slope + t*err}
+}
@@ -0,0 +1,30 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// the dimensions of the logistic map's attractor at r_inf should
+// come out near the Feigenbaum attractor's, in the order D2 <= D1 <= D0
+func TestAttractorDimensions(t *testing.T) {
+    m := maps.Logistic{}
+    dims, err := AttractorDimensions(m, maps.LogisticRInf, m.Crit(maps.LogisticRInf), 100000, 1000000, Scales(1e-2, 1e-6, 25))
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, c := range []struct {
+        name  string
+        fit   DimFit
+        known float64
+    }{{"D0", dims.Box, FeigenbaumD0}, {"D1", dims.Info, FeigenbaumD1}, {"D2", dims.Corr, FeigenbaumD2}} {
+        if math.Abs(c.fit.Dim-c.known) > 0.01 {
+            t.Errorf("%s = %.4f +/- %.4f, want %.3f within 0.01", c.name, c.fit.Dim, c.fit.Err, c.known)
+        }
+    }
+    if dims.Corr.Dim > dims.Info.Dim || dims.Info.Dim > dims.Box.Dim {
+        t.Errorf("want D2 <= D1 <= D0, got %.4f, %.4f, %.4f", dims.Corr.Dim, dims.Info.Dim, dims.Box.Dim)
+    }
+}
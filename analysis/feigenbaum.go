@@ -0,0 +1,96 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// universal Feigenbaum constants (for comparison)
+const (
+    Delta = 4.669201609102990
+    Alpha = 2.502907875095892
+)
+
+// Estimates holds the period-doubling points and the successive
+// ratio estimates of the Feigenbaum constants they give.
+// Deltas[n] = (r_n+1 - r_n) / (r_n+2 - r_n+1) with r_n = Chain[n].R,
+// and Alphas[n] = -w_n / w_n+1 for the widths of the cycles where
+// each loses stability (Deltas[n] and Alphas[n] both use the point
+// Chain[n+2])
+type Estimates struct {
+    Chain  []BifPoint
+    Deltas []float64
+    Alphas []float64
+}
+
+// Feigenbaum estimates delta and alpha from the bifurcation points
+// on the grid, continuing the cascade up to period 2^14. It returns
+// an error if fewer than three doublings are found
+func (g *Grid) Feigenbaum(trans int, x0 float64, pmax int) (Estimates, error) {
+    chain := g.Cascade(g.BifurcationPoints(g.Periods(trans, x0, pmax), x0), 1<<14)
+    if len(chain) < 3 {
+        return Estimates{}, fmt.Errorf("need at least three period doublings to estimate delta, found %v", len(chain))
+    }
+    est := Estimates{Chain: chain, Deltas: make([]float64, 0), Alphas: make([]float64, 0)}
+
+    for n := 2; n < len(chain); n++ {
+        est.Deltas = append(est.Deltas, (chain[n-1].R-chain[n-2].R)/(chain[n].R-chain[n-1].R))
+    }
+
+    widths := make([]float64, 0)
+    for _, pt := range chain[1:] {
+        w, ok := CycleWidth(g.Map, pt.Lo, pt.From)
+        if !ok {
+            break
+        }
+        widths = append(widths, w)
+    }
+    for n := 1; n < len(widths); n++ {
+        est.Alphas = append(est.Alphas, -widths[n-1]/widths[n])
+    }
+    return est, nil
+}
+
+// CycleWidth measures, for the p-cycle at r, the distance from the
+// critical point to the cycle point half a period away (the scaling
+// constant alpha is the limiting ratio of successive widths). It
+// returns the signed width x_c - f^(p/2)(x_c), where x_c is the
+// cycle point nearest the critical point, and whether the cycle
+// was found
+func CycleWidth(m maps.Map1D, r float64, p int) (float64, bool) {
+    c := m.Crit(r)
+    x, ok := FindCycle(m, r, Settle(m, r, c), p)
+    if !ok {
+        return 0, false
+    }
+    nearest := x
+    for k := 0; k < p; k++ {
+        x = m.F(r, x)
+        if math.Abs(x-c) < math.Abs(nearest-c) {
+            nearest = x
+        }
+    }
+    half := nearest
+    for k := 0; k < p/2; k++ {
+        half = m.F(r, half)
+    }
+    return nearest - half, true
+}
+
+// Aitken is Aitken's delta-squared extrapolation of a[n] from
+// a[n-2:n+1]
+func Aitken(a []float64, n int) float64 {
+    den := a[n] - 2*a[n-1] + a[n-2]
+    if den == 0 {
+        return a[n]
+    }
+    return a[n] - (a[n]-a[n-1])*(a[n]-a[n-1])/den
+}
+
+// Richardson extrapolates a[n] assuming the error shrinks by a
+// factor q per step
+func Richardson(a []float64, n int, q float64) float64 {
+    return (a[n] - q*a[n-1]) / (1 - q)
+}
@@ -0,0 +1,43 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// delta and alpha estimated from the bifurcation points should
+// converge on the universal values for maps with a quadratic maximum
+func TestFeigenbaum(t *testing.T) {
+    for _, name := range []string{"logistic", "sine", "gauss"} {
+        m := maps.Family[name]
+        r_lo, r_hi := m.RRange()
+        x_lo, x_hi := m.XRange()
+        g, err := NewGrid(m, r_lo, r_hi, x_lo, x_hi)
+        if err != nil {
+            t.Fatal(err)
+        }
+        est, err := g.Feigenbaum(2000, m.Crit(r_lo), 64)
+        if err != nil {
+            t.Fatalf("%s: %v", name, err)
+        }
+        for _, c := range []struct {
+            name  string
+            est   []float64
+            known float64
+        }{{"delta", est.Deltas, Delta}, {"alpha", est.Alphas, Alpha}} {
+            if len(c.est) < 5 {
+                t.Errorf("%s: only %v %s estimates", name, len(c.est), c.name)
+                continue
+            }
+            first, last := c.est[0], c.est[len(c.est)-1]
+            if math.Abs(last-c.known) > 1e-4 {
+                t.Errorf("%s: %s_%d = %.6f, want %.4f within 1e-4", name, c.name, len(c.est), last, c.known)
+            }
+            if math.Abs(last-c.known) > 1e-2*math.Abs(first-c.known) {
+                t.Errorf("%s: %s estimates went from %.6f to %.6f, want the error down a hundredfold", name, c.name, first, last)
+            }
+        }
+    }
+}
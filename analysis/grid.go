@@ -0,0 +1,154 @@
+////////////////////////////////////////////////////////
+// Purpose: Analyses of one-dimensional maps: periods //
+// and bifurcations, the Feigenbaum constants,        //
+// attracting cycles, Liapunov exponents, convergence //
+// rates and sensitive dependence                     //
+////////////////////////////////////////////////////////
+package analysis
+
+import (
+    "fmt"
+    "math"
+    "runtime"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// grid resolution used for every map (0.001 steps in r and
+// 0.01 steps in x0 for the logistic map)
+const (
+    GridR = 4000
+    GridX = 100
+)
+
+// Grid describes the (r, x0) grid a map is studied on. Cells are
+// only iterated when an analysis asks for them, on a bounded pool
+// of workers
+type Grid struct {
+    Map maps.Map1D
+    RMin, RStep float64
+    XMin, XStep float64
+    NR, NX int
+
+    // shared between copies of the grid
+    Stats *Stats
+}
+
+// Stats is the work done on a grid, for reporting at the end of a run
+type Stats struct {
+    Calc  time.Duration
+    Cells int64
+    Iters int64
+
+    // depth of the Timed calls in progress
+    timing int
+}
+
+// NewGrid returns a GridR x GridX grid over [r_min, r_max) and
+// [x_min, x_max)
+func NewGrid(m maps.Map1D, r_min, r_max, x_min, x_max float64) (*Grid, error) {
+    if r_min >= r_max || x_min >= x_max {
+        return nil, fmt.Errorf("need rmin < rmax and xmin < xmax")
+    }
+    return &Grid{Map: m,
+        RMin: r_min, RStep: (r_max - r_min) / GridR, NR: GridR,
+        XMin: x_min, XStep: (x_max - x_min) / GridX, NX: GridX,
+        Stats: &Stats{}}, nil
+}
+
+// Pool runs job(0) ... job(n-1) on GOMAXPROCS workers and returns
+// once every job has finished
+func Pool(n int, job func(i int)) {
+    workers := runtime.GOMAXPROCS(0)
+    jobs := make(chan int, workers)
+    var wg sync.WaitGroup
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range jobs {
+                job(i)
+            }
+        }()
+    }
+    for i := 0; i < n; i++ {
+        jobs <- i
+    }
+    close(jobs)
+    wg.Wait()
+}
+
+// Sweep is Pool, with the time spent counted as calculation
+func (g *Grid) Sweep(n int, job func(i int)) {
+    g.Timed(func() { Pool(n, job) })
+}
+
+// Timed runs f, counting the time spent as calculation. Calls
+// nested inside f (such as the Sweep of an analysis that is timed as
+// a whole) are only counted once, which relies on Timed and Sweep
+// being called from the one goroutine running the analysis
+func (g *Grid) Timed(f func()) {
+    g.Stats.timing++
+    defer func() { g.Stats.timing-- }()
+    if g.Stats.timing > 1 {
+        f()
+        return
+    }
+    start := time.Now()
+    f()
+    g.Stats.Calc += time.Since(start)
+}
+
+// Orbit iterates a single grid cell and returns keep iterates
+// after the first skip
+func (g *Grid) Orbit(ir, ix, skip, keep int) []float64 {
+    atomic.AddInt64(&g.Stats.Cells, 1)
+    atomic.AddInt64(&g.Stats.Iters, int64(skip+keep))
+    return maps.Gen(g.Map, g.R(ir), g.X(ix), skip, keep)
+}
+
+// Iterate returns x_n for a single grid cell
+func (g *Grid) Iterate(ir, ix, n int) float64 {
+    return g.Orbit(ir, ix, n, 1)[0]
+}
+
+// R is the r value at grid index i (i = NR gives the top of the
+// window)
+func (g *Grid) R(i int) float64 {
+    return g.RMin + float64(i)*g.RStep
+}
+
+// X is the x0 value at grid index j
+func (g *Grid) X(j int) float64 {
+    return g.XMin + float64(j)*g.XStep
+}
+
+// RIndex is the grid index holding r
+func (g *Grid) RIndex(r float64) int {
+    return clamp_idx((r-g.RMin)/g.RStep, g.NR)
+}
+
+// XIndex is the grid index holding x0
+func (g *Grid) XIndex(x0 float64) int {
+    return clamp_idx((x0-g.XMin)/g.XStep, g.NX)
+}
+
+// truncate a fractional grid position to a valid index (the small
+// offset keeps 0.3/0.01 = 29.999... from landing on 29)
+func clamp_idx(pos float64, length int) int {
+    i := int(math.Floor(pos + 1e-9))
+    if i < 0 {
+        return 0
+    } else if i >= length {
+        return length - 1
+    }
+    return i
+}
+
+// number of points in [lo, hi] with step d
+func window_steps(lo, hi, d float64) int {
+    return int(math.Floor((hi-lo)/d+1e-9)) + 1
+}
@@ -0,0 +1,194 @@
+package analysis
+
+import (
+    "math"
+    "sort"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// Kneading returns the first n symbols of the kneading sequence of
+// m at r, the itinerary of f(c), f^2(c), ... of the critical point
+// c: 'L' left of c, 'R' right of it and 'C' on it (the sequence
+// stops after a C). Only unimodal maps with a maximum at c (every
+// map in maps.Family, taking the positive half of the cubic) have
+// a meaningful sequence, and in the chaotic region round-off
+// scrambles the symbols after a few dozen iterations
+func Kneading(m maps.Map1D, r float64, n int) string {
+    c := m.Crit(r)
+    word := make([]byte, 0, n)
+    x := c
+    for i := 0; i < n; i++ {
+        x = m.F(r, x)
+        switch {
+        case x < c:
+            word = append(word, 'L')
+        case x > c:
+            word = append(word, 'R')
+        default:
+            return string(append(word, 'C'))
+        }
+    }
+    return string(word)
+}
+
+// Entropy is the topological entropy -ln(s) given by a kneading
+// sequence, where s is the smallest zero in (0, 1) of the kneading
+// determinant D(t) = 1 + sum_n theta_n t^n with
+// theta_n = e_1 ... e_n, e = +1 for L, -1 for R and 0 for C (the
+// map increases left of c). It is 0 when D has no zero below 1,
+// and for a sequence that doesn't end in C entropies too small for
+// its length to pin down (below about 2 ln(len(word))/len(word))
+// can't be told from 0 and come out as 0
+func Entropy(word string) float64 {
+    if word == "" {
+        return 0
+    }
+    theta := make([]float64, len(word))
+    prod := 1.
+    for i := range word {
+        switch word[i] {
+        case 'R':
+            prod = -prod
+        case 'C':
+            prod = 0
+        }
+        theta[i] = prod
+    }
+    det := func(t float64) float64 {
+        d := 0.
+        for i := len(theta) - 1; i >= 0; i-- {
+            d = (d + theta[i]) * t
+        }
+        return 1 + d
+    }
+
+    // the sequence stops short of the infinite determinant by a
+    // tail of at most t^(N+1)/(1-t) with N = len(word) (nothing
+    // after a C), so D is only known to be positive or negative
+    // where it is further than that from 0. h <= ln 2 for a unimodal
+    // map, so the zero is above 1/2, and it is the first t where D is
+    // certainly negative (the spurious zeros the truncation puts
+    // near 1 never are)
+    tail := func(t float64) float64 {
+        if word[len(word)-1] == 'C' {
+            return 0
+        }
+        return math.Pow(t, float64(len(word)+1)) / (1 - t)
+    }
+    const steps = 1000
+    lo := 0.5 - 1e-9
+    for i := 1; i <= steps; i++ {
+        hi := 0.5 + 0.5*float64(i)/steps
+        if hi >= 1 {
+            break
+        }
+        switch d_hi := det(hi); {
+        case d_hi > tail(hi):
+            lo = hi
+        case d_hi < -tail(hi):
+            return -math.Log(bisect(det, lo, hi, det(lo)))
+        }
+    }
+    return 0
+}
+
+// EntropyCurve returns the kneading sequence (n symbols) and the
+// topological entropy for r in [r_lo, r_hi] with step dr, along
+// with the r values
+func (g *Grid) EntropyCurve(r_lo, r_hi, dr float64, n int) ([]float64, []float64, []string) {
+    n_pts := window_steps(r_lo, r_hi, dr)
+    rs := make([]float64, n_pts)
+    hs := make([]float64, n_pts)
+    words := make([]string, n_pts)
+    g.Sweep(n_pts, func(i int) {
+        rs[i] = r_lo + float64(i)*dr
+        words[i] = Kneading(g.Map, rs[i], n)
+        hs[i] = Entropy(words[i])
+    })
+    return rs, hs, words
+}
+
+// MSSOrbit is a superstable periodic orbit: the parameter R where
+// the critical point lies on a cycle of the given Period, and its
+// kneading word (the symbols of f(c) ... f^(p-1)(c), empty for the
+// superstable fixed point)
+type MSSOrbit struct {
+    Period int
+    R      float64
+    Word   string
+}
+
+// MSS lists every superstable orbit of period up to pmax with r in
+// [r_lo, r_hi], in order of increasing r. Together their words
+// make the Metropolis-Stein-Stein sequence, the order periodic
+// windows open in for every unimodal map. Roots of f^p(c) = c are
+// found on a scan fine enough to split the windows of period pmax
+func MSS(m maps.Map1D, r_lo, r_hi float64, pmax int) []MSSOrbit {
+    pad := 1e-9 * (r_hi - r_lo)
+    found := make([][]MSSOrbit, pmax+1)
+    Pool(pmax, func(i int) {
+        p := i + 1
+        steps := 1 << uint(2*p+4)
+        dr := (r_hi - r_lo - 2*pad) / float64(steps)
+        g := func(r float64) float64 {
+            v, _ := superstable_g(m, r, p)
+            return v
+        }
+        a, g_a := r_lo+pad, g(r_lo+pad)
+        for s := 1; s <= steps; s++ {
+            b := r_lo + pad + float64(s)*dr
+            g_b := g(b)
+            if !math.IsNaN(g_a) && !math.IsNaN(g_b) && (g_a > 0) != (g_b > 0) {
+                r := bisect(g, a, b, g_a)
+                if orb, ok := mss_orbit(m, r, p); ok {
+                    found[p] = append(found[p], orb)
+                }
+            }
+            a, g_a = b, g_b
+        }
+    })
+
+    orbits := make([]MSSOrbit, 0)
+    for _, f := range found {
+        orbits = append(orbits, f...)
+    }
+    sort.Slice(orbits, func(i, j int) bool { return orbits[i].R < orbits[j].R })
+    return orbits
+}
+
+// root of g in [a, b] (where g changes sign, g(a) = g_a) by bisection
+func bisect(g func(float64) float64, a, b, g_a float64) float64 {
+    for j := 0; j < 100 && b-a > 1e-15*math.Max(1, math.Abs(a)); j++ {
+        mid := (a + b) / 2
+        if (g(mid) > 0) == (g_a > 0) {
+            a = mid
+        } else {
+            b = mid
+        }
+    }
+    return (a + b) / 2
+}
+
+// the orbit of the critical point at a root of f^p(c) = c, if it
+// really has period p (roots for the divisors of p turn up too)
+func mss_orbit(m maps.Map1D, r float64, p int) (MSSOrbit, bool) {
+    c := m.Crit(r)
+    x := c
+    word := make([]byte, 0, p)
+    for k := 1; k < p; k++ {
+        x = m.F(r, x)
+        if math.Abs(x-c) < 1e-7 {
+            return MSSOrbit{}, false
+        }
+        if x < c {
+            word = append(word, 'L')
+        } else {
+            word = append(word, 'R')
+        }
+    }
+    if math.Abs(m.F(r, x)-c) > 1e-6 {
+        return MSSOrbit{}, false
+    }
+    return MSSOrbit{Period: p, R: r, Word: string(word)}, true
+}
@@ -0,0 +1,86 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// the logistic map at r = 4 sends c to 1 and on to the fixed point at
+// 0, which has entropy ln 2; before r_inf the entropy is 0
+func TestKneadingLogistic(t *testing.T) {
+    m := maps.Logistic{}
+    word := Kneading(m, 4, 40)
+    if word[:5] != "RLLLL" {
+        t.Errorf("kneading sequence at r = 4 starts %v, want RLLLL", word[:5])
+    }
+    if h := Entropy(word); math.Abs(h-math.Ln2) > 1e-9 {
+        t.Errorf("entropy at r = 4 is %.12f, want ln 2", h)
+    }
+    for _, r := range []float64{2.5, 3.2, 3.5, 3.56, maps.LogisticRInf - 1e-4} {
+        if h := Entropy(Kneading(m, r, 200)); h != 0 {
+            t.Errorf("entropy at r = %v is %v, want 0", r, h)
+        }
+    }
+    // c is the superstable fixed point at r = 2
+    if word := Kneading(m, 2, 10); word != "C" {
+        t.Errorf("kneading sequence at r = 2 is %v, want C", word)
+    }
+}
+
+// closed-form entropies: the superstable 3-cycle (RLC) has entropy
+// ln of the golden mean, and the 2^n-cycles of the cascade have 0
+func TestEntropyWords(t *testing.T) {
+    for _, c := range []struct {
+        word string
+        want float64
+    }{
+        {"RLC", math.Log((1 + math.Sqrt(5)) / 2)},
+        {"RC", 0},
+        {"RLRC", 0},
+        {"RLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLLL", math.Ln2},
+    } {
+        if h := Entropy(c.word); math.Abs(h-c.want) > 1e-9 {
+            t.Errorf("entropy of %v is %.12f, want %.12f", c.word, h, c.want)
+        }
+    }
+}
+
+// the number of superstable orbits of each period p over the full
+// range of a unimodal map is the count of MSS words, 1, 1, 1, 2, 3,
+// 5, 9, ... (Metropolis, Stein and Stein), and the words come in the
+// same order for every map in the family
+func TestMSS(t *testing.T) {
+    counts := []int{0, 1, 1, 1, 2, 3, 5, 9}
+    pmax := len(counts) - 1
+    lo, hi := maps.Logistic{}.RRange()
+    logistic := MSS(maps.Logistic{}, lo, hi, pmax)
+    per_period := make([]int, pmax+1)
+    last_h := 0.
+    for _, orb := range logistic {
+        per_period[orb.Period]++
+        // the entropy grows with r
+        h := Entropy(orb.Word + "C")
+        if h < last_h-1e-12 {
+            t.Errorf("%v-cycle at r = %v has entropy %v, below the %v before it", orb.Period, orb.R, h, last_h)
+        }
+        last_h = h
+    }
+    for p := 1; p <= pmax; p++ {
+        if per_period[p] != counts[p] {
+            t.Errorf("%v superstable %v-cycles, want %v", per_period[p], p, counts[p])
+        }
+    }
+
+    lo, hi = maps.Sine{}.RRange()
+    sine := MSS(maps.Sine{}, lo, hi, pmax)
+    if len(sine) != len(logistic) {
+        t.Fatalf("%v superstable orbits of the sine map, %v of the logistic map", len(sine), len(logistic))
+    }
+    for i := range sine {
+        if sine[i].Period != logistic[i].Period || sine[i].Word != logistic[i].Word {
+            t.Errorf("orbit %v: sine %v %v, logistic %v %v", i, sine[i].Period, sine[i].Word, logistic[i].Period, logistic[i].Word)
+        }
+    }
+}
@@ -0,0 +1,212 @@
+package analysis
+
+import (
+    "math"
+    "math/rand"
+    "sort"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// Coupling is how the sites of a Lattice see each other
+type Coupling int
+
+const (
+    // Diffusive couples each site to its two nearest neighbours
+    Diffusive Coupling = iota
+    // Global couples each site to the mean of every site
+    Global
+)
+
+// Boundary is what lies past the two ends of a diffusive Lattice
+type Boundary int
+
+const (
+    // Periodic joins the ends into a ring
+    Periodic Boundary = iota
+    // Fixed holds a site with the value Edge beyond each end
+    Fixed
+)
+
+// Lattice is a coupled map lattice: a row of sites each running the
+// map m at r, mixed every step with weight Eps,
+//   diffusive: x_i -> (1-Eps) f(x_i) + Eps/2 (f(x_i-1) + f(x_i+1))
+//   global:    x_i -> (1-Eps) f(x_i) + Eps <f(x)>
+// with <f(x)> the mean over all sites
+type Lattice struct {
+    Map      maps.Map1D
+    R, Eps   float64
+    Coupling Coupling
+    Boundary Boundary
+    Edge     float64
+}
+
+// Step moves the lattice from x on one step into next (which must
+// be as long as x and not the same slice)
+func (l Lattice) Step(x, next []float64) {
+    n := len(x)
+    fx := make([]float64, n)
+    mean := 0.
+    for i, xi := range x {
+        fx[i] = l.Map.F(l.R, xi)
+        mean += fx[i]
+    }
+    mean /= float64(n)
+
+    if l.Coupling == Global {
+        for i := range x {
+            next[i] = (1-l.Eps)*fx[i] + l.Eps*mean
+        }
+        return
+    }
+    edge := l.Map.F(l.R, l.Edge)
+    for i := range x {
+        var left, right float64
+        switch {
+        case i > 0:
+            left = fx[i-1]
+        case l.Boundary == Periodic:
+            left = fx[n-1]
+        default:
+            left = edge
+        }
+        switch {
+        case i < n-1:
+            right = fx[i+1]
+        case l.Boundary == Periodic:
+            right = fx[0]
+        default:
+            right = edge
+        }
+        next[i] = (1-l.Eps)*fx[i] + l.Eps/2*(left+right)
+    }
+}
+
+// Run steps the lattice trans times from x0 and returns the next
+// keep states, one row per step (the space-time pattern)
+func (l Lattice) Run(x0 []float64, trans, keep int) [][]float64 {
+    x := append([]float64(nil), x0...)
+    next := make([]float64, len(x))
+    for i := 0; i < trans; i++ {
+        l.Step(x, next)
+        x, next = next, x
+    }
+    frames := make([][]float64, keep)
+    for i := range frames {
+        l.Step(x, next)
+        x, next = next, x
+        frames[i] = append([]float64(nil), x...)
+    }
+    return frames
+}
+
+// RandomState is n sites drawn uniformly from the map's x range
+// using rng, for a reproducible disordered start
+func RandomState(m maps.Map1D, n int, rng *rand.Rand) []float64 {
+    lo, hi := m.XRange()
+    x := make([]float64, n)
+    for i := range x {
+        x[i] = lo + (hi-lo)*rng.Float64()
+    }
+    return x
+}
+
+// PatternStats summarises a space-time pattern from Lattice.Run
+type PatternStats struct {
+    // spatial standard deviation, averaged over the frames and in
+    // the last frame (0 when every site is in step)
+    SyncErr, FinalErr float64
+    // period in time of the whole lattice and in space of the last
+    // frame (0 when there's none up to pmax)
+    Period, Wavelength int
+    // number of distinct values in the last frame
+    Clusters int
+    Class    string
+}
+
+// Classify works out the PatternStats of frames, counting values
+// within tol as equal and looking for periods up to pmax. The class
+// is one of "synchronized" (all sites equal), "frozen" (a fixed
+// pattern), "periodic", "clustered" (no period, but the sites have
+// split into a few groups moving together) or "spatiotemporal
+// chaos"
+func Classify(frames [][]float64, pmax int, tol float64) PatternStats {
+    var s PatternStats
+    if len(frames) == 0 {
+        return s
+    }
+    spread := func(x []float64) float64 {
+        mean, sq := 0., 0.
+        for _, xi := range x {
+            mean += xi
+        }
+        mean /= float64(len(x))
+        for _, xi := range x {
+            sq += (xi - mean) * (xi - mean)
+        }
+        return math.Sqrt(sq / float64(len(x)))
+    }
+    for _, f := range frames {
+        s.SyncErr += spread(f)
+    }
+    s.SyncErr /= float64(len(frames))
+    last := frames[len(frames)-1]
+    s.FinalErr = spread(last)
+
+    // whether a[i] matches b[i+shift] wherever both exist
+    same := func(a, b []float64, shift int) bool {
+        for i := 0; i+shift < len(b); i++ {
+            if math.Abs(a[i]-b[i+shift]) > tol {
+                return false
+            }
+        }
+        return true
+    }
+    for p := 1; p <= pmax && p < len(frames); p++ {
+        if same(last, frames[len(frames)-1-p], 0) {
+            s.Period = p
+            break
+        }
+    }
+    for w := 1; w <= pmax && w <= len(last)/2; w++ {
+        if same(last, last, w) {
+            s.Wavelength = w
+            break
+        }
+    }
+
+    vals := append([]float64(nil), last...)
+    sort.Float64s(vals)
+    s.Clusters = 1
+    for i := 1; i < len(vals); i++ {
+        if vals[i]-vals[i-1] > tol {
+            s.Clusters++
+        }
+    }
+
+    switch {
+    case s.FinalErr < tol:
+        s.Class = "synchronized"
+    case s.Period == 1:
+        s.Class = "frozen"
+    case s.Period > 1:
+        s.Class = "periodic"
+    case 4*s.Clusters <= len(last):
+        s.Class = "clustered"
+    default:
+        s.Class = "spatiotemporal chaos"
+    }
+    return s
+}
+
+// SyncSweep runs the lattice l from x0 for each coupling in epss
+// and classifies the last keep steps after trans
+func SyncSweep(l Lattice, x0, epss []float64, trans, keep, pmax int, tol float64) []PatternStats {
+    stats := make([]PatternStats, len(epss))
+    Pool(len(epss), func(i int) {
+        li := l
+        li.Eps = epss[i]
+        stats[i] = Classify(li.Run(x0, trans, keep), pmax, tol)
+    })
+    return stats
+}
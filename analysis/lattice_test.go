@@ -0,0 +1,78 @@
+package analysis
+
+import (
+    "math"
+    "math/rand"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// the in-step state of a lattice of logistic maps at r = 4 (Liapunov
+// exponent ln 2) is stable when every transverse mode shrinks, which
+// is |1 - Eps| < 1/2 for global coupling and
+// |1 - Eps (1 - cos(2 pi k/n))| < 1/2 for every mode k of a ring of
+// n sites: Eps > 1/2 globally, 1/2 < Eps < 3/4 on a ring of 4 and
+// 1/3 < Eps < 1 on a ring of 3
+func TestSyncThresholds(t *testing.T) {
+    stable := func(c Coupling, n int, eps float64) bool {
+        if c == Global {
+            return 2*math.Abs(1-eps) < 1
+        }
+        for k := 1; k < n; k++ {
+            if 2*math.Abs(1-eps*(1-math.Cos(2*math.Pi*float64(k)/float64(n)))) >= 1 {
+                return false
+            }
+        }
+        return true
+    }
+    epss := make([]float64, 0)
+    for i := 1; i < 20; i++ {
+        epss = append(epss, 0.05*float64(i)-0.01)
+    }
+
+    for _, c := range []struct {
+        coupling Coupling
+        sites    int
+    }{{Global, 16}, {Diffusive, 4}, {Diffusive, 3}} {
+        l := Lattice{Map: maps.Logistic{}, R: 4, Coupling: c.coupling, Boundary: Periodic}
+        x0 := RandomState(l.Map, c.sites, rand.New(rand.NewSource(1)))
+        for i, s := range SyncSweep(l, x0, epss, 5000, 100, 64, 1e-6) {
+            if want := stable(c.coupling, c.sites, epss[i]); (s.Class == "synchronized") != want {
+                t.Errorf("coupling %v, %v sites, eps = %.2f: %v (sync err %.3g), want in step %v", c.coupling, c.sites, epss[i], s.Class, s.FinalErr, want)
+            }
+        }
+    }
+}
+
+// hand-made patterns of each class
+func TestClassify(t *testing.T) {
+    frames := func(n int, row func(t, i int) float64) [][]float64 {
+        f := make([][]float64, n)
+        for t := range f {
+            f[t] = make([]float64, 8)
+            for i := range f[t] {
+                f[t][i] = row(t, i)
+            }
+        }
+        return f
+    }
+    cases := []struct {
+        name   string
+        frames [][]float64
+        class  string
+        period int
+        wave   int
+    }{
+        {"synchronized", frames(10, func(t, i int) float64 { return 0.1 * float64(t%3) }), "synchronized", 3, 1},
+        {"frozen", frames(10, func(t, i int) float64 { return 0.2 * float64(i%2) }), "frozen", 1, 2},
+        {"periodic", frames(10, func(t, i int) float64 { return 0.2 * float64((i+t)%4) }), "periodic", 4, 4},
+        {"spatiotemporal chaos", frames(10, func(t, i int) float64 { return math.Mod(float64(t*8+i)*0.618034, 1) }), "spatiotemporal chaos", 0, 0},
+    }
+    for _, c := range cases {
+        s := Classify(c.frames, 5, 1e-6)
+        if s.Class != c.class || s.Period != c.period || s.Wavelength != c.wave {
+            t.Errorf("%s: class %v, period %v, wavelength %v; want %v, %v, %v", c.name, s.Class, s.Period, s.Wavelength, c.class, c.period, c.wave)
+        }
+    }
+}
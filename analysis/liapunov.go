@@ -0,0 +1,72 @@
+package analysis
+
+import (
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// LiapFloor is the lowest exponent worth showing (superstable
+// points dip far below everything else)
+const LiapFloor = -4
+
+// LogDF returns log|f'(x)|, with x nudged off the critical point
+// when it lands exactly on it (a superstable orbit would otherwise
+// drag the average to -Inf). The nudged x is returned so the orbit
+// can carry on from it
+func LogDF(m maps.Map1D, r, x float64) (float64, float64) {
+    slope := math.Abs(m.DF(r, x))
+    if slope == 0 {
+        x += 1e-12 * math.Max(1, math.Abs(x))
+        slope = math.Abs(m.DF(r, x))
+    }
+    return math.Log(slope), x
+}
+
+// Exponent is the Liapunov exponent for a single r, averaging
+// log|f'(x)| over n_avg iterations after discarding trans
+func Exponent(m maps.Map1D, r, x0 float64, trans, n_avg int) float64 {
+    m = maps.Start(m, r, x0)
+    x := x0
+    for i := 0; i < trans; i++ {
+        x = m.F(r, x)
+    }
+    expo := 0.
+    for i := 0; i < n_avg; i++ {
+        var l float64
+        l, x = LogDF(m, r, x)
+        expo += l
+        x = m.F(r, x)
+    }
+    return expo / float64(n_avg)
+}
+
+// Liapunov returns the exponent from x0 for every r on the grid,
+// averaged over n iterations after the first
+func (g *Grid) Liapunov(n int, x0 float64) []float64 {
+    expos := make([]float64, g.NR)
+    x_ind := g.XIndex(x0)
+    g.Sweep(len(expos), func(i int) {
+        r := g.R(i)
+        expo := 0.
+        for _, x := range g.Orbit(i, x_ind, 1, n) {
+            l, _ := LogDF(g.Map, r, x)
+            expo += l
+        }
+        expos[i] = expo / float64(n)
+    })
+    return expos
+}
+
+// LiapunovCurve returns the exponent from x0 for r in [r_lo, r_hi]
+// with step dr, along with the r values
+func (g *Grid) LiapunovCurve(x0, r_lo, r_hi, dr float64, trans, n_avg int) ([]float64, []float64) {
+    n_pts := window_steps(r_lo, r_hi, dr)
+    rs := make([]float64, n_pts)
+    expos := make([]float64, n_pts)
+    g.Sweep(n_pts, func(i int) {
+        rs[i] = r_lo + float64(i)*dr
+        expos[i] = Exponent(g.Map, rs[i], x0, trans, n_avg)
+    })
+    return rs, expos
+}
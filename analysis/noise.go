@@ -0,0 +1,124 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// Kappa is the scaling factor for noise in the period-doubling
+// cascade: noise has to shrink by Kappa to resolve one more doubling
+const Kappa = 6.619
+
+// NoiseGamma is the predicted exponent of the shift of the onset of
+// chaos with noise amplitude, r_inf - r_c(sigma) ~ sigma^NoiseGamma
+var NoiseGamma = math.Log(Delta) / math.Log(Kappa)
+
+// NoiseSpread is the typical distance of the iterates xs of a
+// noisy orbit at r from where the noiseless orbit would be, from
+// linear propagation of the kicks: a deviation e becomes
+// f'(x) e + k, where k has variance sigma^2 for additive noise and
+// (sigma df/dr)^2 for parametric, so its variance v follows
+// v -> f'(x)^2 v + var(k) along the orbit (taken round xs twice to
+// settle). It returns the root mean square of v over the orbit, and
+// false when the orbit doesn't contract on average (a positive
+// Liapunov exponent), where deviations aren't small and there are no
+// bands to resolve
+func NoiseSpread(m maps.Noisy, r float64, xs []float64) (float64, bool) {
+    h := 1e-6 * math.Max(1, math.Abs(r))
+    kick := func(x float64) float64 {
+        if m.Parametric {
+            return m.Sigma * (m.Map1D.F(r+h, x) - m.Map1D.F(r-h, x)) / (2 * h)
+        }
+        return m.Sigma
+    }
+    v, v_sum, liap := 0., 0., 0.
+    for pass := 0; pass < 2; pass++ {
+        for _, x := range xs {
+            df := m.DF(r, x)
+            v = df*df*v + math.Pow(kick(x), 2)
+            if pass == 1 {
+                v_sum += v
+                liap += math.Log(math.Abs(df))
+            }
+        }
+    }
+    if liap >= 0 || math.IsNaN(liap) {
+        return 0, false
+    }
+    return math.Sqrt(v_sum / float64(len(xs))), true
+}
+
+// BandSplit is how far apart, in units of the mean distance between
+// two noisy iterates on the same band, the iterates p steps apart can
+// be for p to still count as the band period. Two iterates spread by
+// s either side of the same point are 2s/sqrt(pi) apart on average;
+// a gap g between bands adds to that, and with BandSplit = 2 bands
+// count as separate once g is about twice s, where the noise can no
+// longer carry an iterate from one to the other
+const BandSplit = 2
+
+// NoisyPeriod is DetectPeriod for an orbit blurred by noise that
+// spreads its iterates by spread (see NoiseSpread): the smallest
+// p <= pmax with the mean of |xs[i+p] - xs[i]| within PeriodTol +
+// BandSplit*2*spread/sqrt(pi) (single iterates stray too far for
+// DetectPeriod's test). This is the number of bands the orbit cycles
+// through, which stops doubling once the width noise gives the
+// bands reaches the gaps between them; it only grows as the spread
+// shrinks
+func NoisyPeriod(xs []float64, pmax int, spread float64) int {
+    tol := PeriodTol + BandSplit*2*spread/math.Sqrt(math.Pi)
+    for p := 1; p <= pmax && 2*p <= len(xs); p++ {
+        diff := 0.
+        for i := 0; i+p < len(xs); i++ {
+            diff += math.Abs(xs[i+p] - xs[i])
+        }
+        if diff/float64(len(xs)-p) <= tol {
+            return p
+        }
+    }
+    return 0
+}
+
+// NoisyPeriods is Periods for a grid over a maps.Noisy map, using
+// NoisyPeriod on orbits long enough to average over (0 where the
+// orbit is chaotic). For any other map it is Periods
+func (g *Grid) NoisyPeriods(trans int, x0 float64, pmax int) []int {
+    noisy, ok := g.Map.(maps.Noisy)
+    if !ok {
+        return g.Periods(trans, x0, pmax)
+    }
+    x_ind := g.XIndex(x0)
+    periods := make([]int, g.NR)
+    g.Sweep(len(periods), func(i int) {
+        xs := g.Orbit(i, x_ind, trans, 16*pmax)
+        if spread, ok := NoiseSpread(noisy, g.R(i), xs); ok {
+            periods[i] = NoisyPeriod(xs, pmax, spread)
+        }
+    })
+    return periods
+}
+
+// ChaosOnset bisects r in [r_lo, r_hi] for where the Liapunov
+// exponent of m with noise of amplitude sigma (as in maps.NewNoisy,
+// each r tried drawing from the stream seeded by seed, r and x0)
+// turns positive, to within tol.
+// The exponent has to be negative at r_lo and positive at r_hi
+func ChaosOnset(m maps.Map1D, sigma float64, parametric bool, seed int64, x0, r_lo, r_hi float64, trans, n_avg int, tol float64) (float64, error) {
+    expo := func(r float64) float64 {
+        return Exponent(maps.NewNoisy(m, sigma, parametric, seed), r, x0, trans, n_avg)
+    }
+    if expo(r_lo) >= 0 || expo(r_hi) <= 0 {
+        return 0, fmt.Errorf("need a negative exponent at r = %v and a positive one at r = %v with noise %v", r_lo, r_hi, sigma)
+    }
+    for r_hi-r_lo > tol {
+        mid := (r_lo + r_hi) / 2
+        if expo(mid) > 0 {
+            r_hi = mid
+        } else {
+            r_lo = mid
+        }
+    }
+    return (r_lo + r_hi) / 2, nil
+}
@@ -0,0 +1,52 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// more noise blurs away the finer bands, so the longest period
+// resolved below r_inf can only shrink as sigma grows
+func TestNoisyPeriodsMonotone(t *testing.T) {
+    for _, parametric := range []bool{false, true} {
+        last := math.MaxInt32
+        for k := 0; k <= 12; k++ {
+            sigma := 1e-7 * math.Pow(10, float64(k)/3)
+            g, err := NewGrid(maps.NewNoisy(maps.Logistic{}, sigma, parametric, 1), 3.4, 3.5699, 0, 1)
+            if err != nil {
+                t.Fatal(err)
+            }
+            max_p := 0
+            for _, p := range g.NoisyPeriods(2000, 0.5, 64) {
+                if p > max_p {
+                    max_p = p
+                }
+            }
+            if max_p == 0 || max_p > last {
+                t.Errorf("parametric = %v, sigma = %.3g: max period %v after %v", parametric, sigma, max_p, last)
+            }
+            last = max_p
+        }
+        // at the smallest noise the bands are still resolved to 64
+        // cycles, at the largest only a handful
+        if last > 8 {
+            t.Errorf("parametric = %v: max period %v at sigma = 1e-3", parametric, last)
+        }
+    }
+}
+
+// without noise NoisyPeriods falls back on Periods
+func TestNoisyPeriodsNoiseless(t *testing.T) {
+    g, err := NewGrid(maps.Logistic{}, 2.8, 3.56, 0, 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := g.Periods(2000, 0.5, 64)
+    for i, p := range g.NoisyPeriods(2000, 0.5, 64) {
+        if p != want[i] {
+            t.Errorf("r = %v: period %v, want %v", g.R(i), p, want[i])
+        }
+    }
+}
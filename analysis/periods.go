@@ -0,0 +1,224 @@
+package analysis
+
+import (
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// PeriodTol is the tolerance used to decide that two iterates are
+// the same point
+const PeriodTol = 1e-6
+
+// MaxGap is the longest run of unresolved grid points that is
+// still treated as slow convergence next to a bifurcation rather
+// than chaos
+const MaxGap = 10
+
+// DetectPeriod finds the period of the attractor sampled by xs
+// (iterates after the transient has died out): the smallest
+// p <= pmax with xs[i+p] == xs[i] for every i (within PeriodTol),
+// or 0 if there is none (chaos, or a period longer than pmax)
+func DetectPeriod(xs []float64, pmax int) int {
+    for p := 1; p <= pmax && 2*p <= len(xs); p++ {
+        found := true
+        for i := 0; i+p < len(xs); i++ {
+            if math.Abs(xs[i+p]-xs[i]) > PeriodTol {
+                found = false
+                break
+            }
+        }
+        if found {
+            return p
+        }
+    }
+    return 0
+}
+
+// Periods returns the attractor period for every r on the grid
+// starting from x0 (0 where it's unresolved)
+func (g *Grid) Periods(trans int, x0 float64, pmax int) []int {
+    x_ind := g.XIndex(x0)
+    periods := make([]int, g.NR)
+    g.Sweep(len(periods), func(i int) {
+        periods[i] = DetectPeriod(g.Orbit(i, x_ind, trans, 2*pmax), pmax)
+    })
+    return periods
+}
+
+// BifPoint is a bifurcation: the periods on either side (From is 0
+// when a window opens out of chaos), the best estimate of r and
+// the interval [Lo, Hi] known to hold it
+type BifPoint struct {
+    From, To  int
+    R, Lo, Hi float64
+}
+
+// BifurcationPoints turns per-r periods into bifurcation points in
+// order of increasing r. Period doublings are refined past the grid
+// resolution by locating where the multiplier of the shorter cycle
+// crosses -1; everything else is bracketed by the grid
+func (g *Grid) BifurcationPoints(periods []int, x0 float64) []BifPoint {
+    points := make([]BifPoint, 0)
+    last := -1
+    for i, p := range periods {
+        if p == 0 {
+            continue
+        }
+        if last >= 0 && periods[last] != p {
+            pt := BifPoint{From: periods[last], To: p, Lo: g.R(last), Hi: g.R(i)}
+            if i-last-1 > MaxGap {
+                // chaos in between: the window opens somewhere in the gap
+                pt.From = 0
+                pt.Lo = g.R(i - 1)
+            }
+            pt.R = (pt.Lo + pt.Hi) / 2
+            if pt.From == 2*pt.To || pt.To == 2*pt.From {
+                g.refine_doubling(&pt, x0)
+            }
+            points = append(points, pt)
+        }
+        last = i
+    }
+    return points
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Bisect on r for the point where the shorter     //
+// cycle of a doubling has multiplier -1. Slow convergence  //
+// can put the grid bracket on the wrong side of the true   //
+// point, so the cycle is first followed along r until its  //
+// stability changes                                        //
+// Return: Nothing (pt is tightened in place, and left as   //
+// the grid bracket if the cycle can't be followed)         //
+//////////////////////////////////////////////////////////////
+func (g *Grid) refine_doubling(pt *BifPoint, x0 float64) {
+    // start on the side of the shorter cycle and walk towards
+    // the longer one
+    p, r, dir := pt.From, pt.Lo, g.RStep
+    if pt.To < pt.From {
+        p, r, dir = pt.To, pt.Hi, -g.RStep
+    }
+    stable := func(r, x float64) bool {
+        return math.Abs(Multiplier(g.Map, r, x, p)) < 1
+    }
+
+    // how far to follow the cycle: MaxGap grid points, or a
+    // quarter of a percent of the map's r range for fine grids
+    r_lo, r_hi := g.Map.RRange()
+    reach := int(math.Max(MaxGap, math.Ceil(0.0025*(r_hi-r_lo)/g.RStep)))
+
+    x, ok := FindCycle(g.Map, r, Settle(g.Map, r, x0), p)
+    for steps := 0; ok && !stable(r, x) && steps < reach; steps++ {
+        r -= dir
+        x, ok = FindCycle(g.Map, r, x, p)
+    }
+    if !ok || !stable(r, x) {
+        return
+    }
+
+    if lo, hi, found := stability_edge(g.Map, p, r, x, dir, 4*reach); found {
+        pt.Lo, pt.Hi, pt.R = lo, hi, (lo+hi)/2
+    }
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Follow the p-cycle through x from r, where it   //
+// is stable, in steps of dr until it loses stability and   //
+// bisect for the crossing                                  //
+// Return: Interval [lo, hi] holding the crossing and       //
+// whether it was found within max_steps                    //
+//////////////////////////////////////////////////////////////
+func stability_edge(m maps.Map1D, p int, r, x, dr float64, max_steps int) (float64, float64, bool) {
+    stable := func(r, x float64) bool {
+        return math.Abs(Multiplier(m, r, x, p)) < 1
+    }
+
+    ok := true
+    next, x_next := r, x
+    for steps := 0; ok && stable(next, x_next) && steps < max_steps; steps++ {
+        r, x = next, x_next
+        next, x_next, ok = follow_cycle(m, p, r, x, dr)
+    }
+    if !ok || stable(next, x_next) {
+        return r, next, false
+    }
+
+    // bisect between the last stable and first unstable r
+    for math.Abs(next-r) > 1e-14*math.Max(1, math.Abs(r)) {
+        mid := (r + next) / 2
+        x_mid, ok := FindCycle(m, mid, x, p)
+        if !ok || Multiplier(m, mid, x_mid, p) >= 1 {
+            break
+        }
+        if stable(mid, x_mid) {
+            r, x = mid, x_mid
+        } else {
+            next = mid
+        }
+    }
+    return math.Min(r, next), math.Max(r, next), true
+}
+
+// the p-cycle through x at r continued to r + dr, halving the step
+// while Newton lands on some other p-cycle: the cycle can only lose
+// stability through a multiplier of -1, so one of +1 or more means
+// it jumped. Return: where it got to, the cycle point there and
+// whether it moved at all
+func follow_cycle(m maps.Map1D, p int, r, x, dr float64) (float64, float64, bool) {
+    for halvings := 0; halvings < 30; halvings++ {
+        x_next, ok := FindCycle(m, r+dr, x, p)
+        if ok && Multiplier(m, r+dr, x_next, p) < 1 {
+            return r + dr, x_next, true
+        }
+        dr /= 2
+    }
+    return r, x, false
+}
+
+// Cascade pulls the period-doubling cascade out of the bifurcation
+// points (1->2->4->... starting at the first doubling) and continues
+// it past the grid resolution, up to max_period or until round-off
+// hides the crossings, by predicting each next point with the latest
+// delta estimate and refining it. The doubling points are returned
+// in order
+func (g *Grid) Cascade(points []BifPoint, max_period int) []BifPoint {
+    chain := make([]BifPoint, 0)
+    for _, pt := range points {
+        if pt.To != 2*pt.From || pt.Hi-pt.Lo >= g.RStep {
+            // not a (refined) doubling
+            if len(chain) > 0 {
+                break
+            }
+            continue
+        }
+        if len(chain) > 0 && chain[len(chain)-1].To != pt.From {
+            break
+        }
+        chain = append(chain, pt)
+    }
+
+    for len(chain) >= 2 && 2*chain[len(chain)-1].To <= max_period {
+        last, prev := chain[len(chain)-1], chain[len(chain)-2]
+        gap := (last.R - prev.R) / Delta
+        if gap < 1e-13 {
+            break
+        }
+
+        // the new cycle is well established a third of the way to
+        // the predicted point, so start following it from there
+        p := last.To
+        r := last.Hi + gap/3
+        x, ok := FindCycle(g.Map, r, Settle(g.Map, r, g.Map.Crit(r)), p)
+        if !ok || math.Abs(Multiplier(g.Map, r, x, p)) >= 1 {
+            break
+        }
+        lo, hi, found := stability_edge(g.Map, p, r, x, gap/8, 40)
+        if !found || hi-lo > 1e-12*math.Max(1, math.Abs(lo)) {
+            // lost the cycle, or round-off hides where it turns
+            break
+        }
+        chain = append(chain, BifPoint{From: p, To: 2 * p, Lo: lo, Hi: hi, R: (lo + hi) / 2})
+    }
+    return chain
+}
@@ -0,0 +1,94 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// orbits with a known period, one that only repeats past pmax and
+// one that never repeats
+func TestDetectPeriod(t *testing.T) {
+    cycle := func(n int, pts ...float64) []float64 {
+        xs := make([]float64, n)
+        for i := range xs {
+            xs[i] = pts[i%len(pts)]
+        }
+        return xs
+    }
+    cases := []struct {
+        name string
+        xs   []float64
+        pmax int
+        want int
+    }{
+        {"fixed point", cycle(20, 0.6), 8, 1},
+        {"2-cycle", cycle(20, 0.3, 0.8), 8, 2},
+        {"3-cycle", cycle(30, 0.1, 0.5, 0.9), 8, 3},
+        {"within PeriodTol", cycle(20, 0.3, 0.8, 0.3+PeriodTol/2, 0.8), 8, 2},
+        {"past PeriodTol", cycle(20, 0.3, 0.8, 0.3+2*PeriodTol, 0.8), 8, 4},
+        {"longer than pmax", cycle(40, 0.1, 0.2, 0.3, 0.4, 0.5), 4, 0},
+        {"too short to tell", cycle(5, 0.1, 0.5, 0.9), 8, 0},
+        {"chaos", maps.Gen(maps.Logistic{}, 4, 0.1, 1000, 200), 64, 0},
+    }
+    for _, c := range cases {
+        if p := DetectPeriod(c.xs, c.pmax); p != c.want {
+            t.Errorf("%s: period %v, want %v", c.name, p, c.want)
+        }
+    }
+}
+
+// periods of the logistic map on either side of its first doublings,
+// in the period 3 window and in the chaotic band
+func TestPeriods(t *testing.T) {
+    g, err := NewGrid(maps.Logistic{}, 0, 4, 0, 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    periods := g.Periods(2000, 0.5, 64)
+    for _, c := range []struct {
+        r    float64
+        want int
+    }{{2.8, 1}, {3.2, 2}, {3.5, 4}, {3.56, 8}, {3.835, 3}, {3.9, 0}, {4, 0}} {
+        if p := periods[g.RIndex(c.r)]; p != c.want {
+            t.Errorf("r = %v: period %v, want %v", c.r, p, c.want)
+        }
+    }
+}
+
+// the first two doublings of the logistic map are at r = 3 and
+// 1 + sqrt(6), and the period 3 window opens at 1 + sqrt(8)
+func TestBifurcationPoints(t *testing.T) {
+    g, err := NewGrid(maps.Logistic{}, 2.5, 3.9, 0, 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    points := g.BifurcationPoints(g.Periods(2000, 0.5, 64), 0.5)
+    if len(points) < 2 {
+        t.Fatalf("found %v bifurcation points", len(points))
+    }
+    for i, want := range []float64{3, 1 + math.Sqrt(6)} {
+        pt := points[i]
+        if pt.From != 1<<uint(i) || pt.To != 2<<uint(i) {
+            t.Errorf("point %v: %v -> %v, want %v -> %v", i, pt.From, pt.To, 1<<uint(i), 2<<uint(i))
+        }
+        if math.Abs(pt.R-want) > 1e-9 || pt.Lo > want || pt.Hi < want {
+            t.Errorf("%v -> %v: r = %.12f [%.12f, %.12f], want %.12f", pt.From, pt.To, pt.R, pt.Lo, pt.Hi, want)
+        }
+    }
+
+    window := 1 + math.Sqrt(8)
+    found := false
+    for _, pt := range points {
+        if pt.From == 0 && pt.To == 3 {
+            found = true
+            if pt.Lo > window || pt.Hi < window {
+                t.Errorf("period 3 window opens in [%v, %v], want it to hold %v", pt.Lo, pt.Hi, window)
+            }
+        }
+    }
+    if !found {
+        t.Errorf("no period 3 window in %v", points)
+    }
+}
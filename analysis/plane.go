@@ -0,0 +1,202 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+    "runtime"
+    "sync/atomic"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// EscapeBound is how far out an orbit of a Map2D can get before it
+// counts as gone to infinity
+const EscapeBound = 1e8
+
+// Rect is a window [XLo, XHi] x [YLo, YHi] of the plane
+type Rect struct {
+    XLo, XHi, YLo, YHi float64
+}
+
+// Zoom returns the window f times smaller than w, centred on (x, y)
+func (w Rect) Zoom(x, y, f float64) Rect {
+    dx, dy := (w.XHi-w.XLo)/(2*f), (w.YHi-w.YLo)/(2*f)
+    return Rect{XLo: x - dx, XHi: x + dx, YLo: y - dy, YHi: y + dy}
+}
+
+func escaped(x, y float64) bool {
+    return !(math.Abs(x) < EscapeBound && math.Abs(y) < EscapeBound)
+}
+
+// Orbit2D iterates m from (x0, y0), throws away trans iterates and
+// returns the next n. An orbit that escapes returns an error along
+// with the points before it did
+func Orbit2D(m maps.Map2D, x0, y0 float64, trans, n int) ([]float64, []float64, error) {
+    x, y := x0, y0
+    for i := 0; i < trans; i++ {
+        x, y = m.F(x, y)
+        if escaped(x, y) {
+            return nil, nil, fmt.Errorf("orbit of (%v, %v) escaped during the transient (step %v)", x0, y0, i+1)
+        }
+    }
+    xs := make([]float64, 0, n)
+    ys := make([]float64, 0, n)
+    for i := 0; i < n; i++ {
+        x, y = m.F(x, y)
+        if escaped(x, y) {
+            return xs, ys, fmt.Errorf("orbit of (%v, %v) escaped at step %v", x0, y0, trans+i+1)
+        }
+        xs, ys = append(xs, x), append(ys, y)
+    }
+    return xs, ys, nil
+}
+
+// Spectrum returns both Liapunov exponents of m, largest first,
+// averaged over n steps after trans from (x0, y0). Two tangent
+// vectors are carried through the Jacobian and re-orthonormalised
+// every step (Gram-Schmidt, the QR decomposition of the product of
+// Jacobians), so the first grows at the largest rate and the
+// second at the rate left over
+func Spectrum(m maps.Map2D, x0, y0 float64, trans, n int) ([2]float64, error) {
+    var l [2]float64
+    x, y, ok := last_transient(m, x0, y0, trans)
+    if !ok {
+        return l, fmt.Errorf("orbit of (%v, %v) escaped during the transient", x0, y0)
+    }
+
+    u := [2]float64{1, 0}
+    v := [2]float64{0, 1}
+    mul := func(j [2][2]float64, w [2]float64) [2]float64 {
+        return [2]float64{j[0][0]*w[0] + j[0][1]*w[1], j[1][0]*w[0] + j[1][1]*w[1]}
+    }
+    for i := 0; i < n; i++ {
+        j := m.Jacobian(x, y)
+        u, v = mul(j, u), mul(j, v)
+
+        nu := math.Hypot(u[0], u[1])
+        u[0], u[1] = u[0]/nu, u[1]/nu
+        dot := v[0]*u[0] + v[1]*u[1]
+        v[0], v[1] = v[0]-dot*u[0], v[1]-dot*u[1]
+        nv := math.Hypot(v[0], v[1])
+        v[0], v[1] = v[0]/nv, v[1]/nv
+
+        l[0] += math.Log(nu)
+        l[1] += math.Log(nv)
+        x, y = m.F(x, y)
+        if escaped(x, y) {
+            return l, fmt.Errorf("orbit of (%v, %v) escaped at step %v", x0, y0, trans+i+1)
+        }
+    }
+    l[0] /= float64(n)
+    l[1] /= float64(n)
+    return l, nil
+}
+
+// the point after trans steps from (x0, y0)
+func last_transient(m maps.Map2D, x0, y0 float64, trans int) (float64, float64, bool) {
+    x, y := x0, y0
+    for i := 0; i < trans; i++ {
+        x, y = m.F(x, y)
+    }
+    return x, y, !escaped(x, y)
+}
+
+// KaplanYorke is the Liapunov dimension 1 + l1/|l2| of an attractor
+// of a 2D map with exponents l (largest first): 0 when l1 < 0 (a
+// stable cycle) and 2 when l1 + l2 >= 0
+func KaplanYorke(l [2]float64) float64 {
+    switch {
+    case l[0] < 0:
+        return 0
+    case l[0]+l[1] >= 0:
+        return 2
+    }
+    return 1 + l[0]/math.Abs(l[1])
+}
+
+// PlaneDensity is the histogram of the attractor of m within win,
+// over width x height bins counted row by row from YHi down. One
+// orbit per worker starts near (x0, y0) and runs trans steps
+// before counting, and they stop once hits points have landed in
+// win or max_iter steps have been taken between them, so zooms
+// into a small part of the attractor can ask for the same number
+// of hits. Returns the counts and the number of steps taken
+func PlaneDensity(m maps.Map2D, x0, y0 float64, win Rect, width, height, trans int, hits, max_iter int64) ([]float64, int64, error) {
+    workers := runtime.GOMAXPROCS(0)
+    parts := make([][]float64, workers)
+    errs := make([]error, workers)
+    var n_hits, n_iter int64
+    const batch = 1 << 14
+    Pool(workers, func(w int) {
+        counts := make([]float64, width*height)
+        parts[w] = counts
+        x, y, ok := last_transient(m, x0+1e-9*float64(w), y0, trans)
+        if !ok {
+            errs[w] = fmt.Errorf("orbit of (%v, %v) escaped during the transient", x0, y0)
+            return
+        }
+        for atomic.LoadInt64(&n_hits) < hits && atomic.AddInt64(&n_iter, batch) <= max_iter {
+            got := int64(0)
+            for i := 0; i < batch; i++ {
+                x, y = m.F(x, y)
+                if escaped(x, y) {
+                    errs[w] = fmt.Errorf("orbit escaped from the attractor")
+                    return
+                }
+                col := int(math.Floor((x - win.XLo) / (win.XHi - win.XLo) * float64(width)))
+                row := int(math.Floor((win.YHi - y) / (win.YHi - win.YLo) * float64(height)))
+                if col >= 0 && col < width && row >= 0 && row < height {
+                    counts[row*width+col]++
+                    got++
+                }
+            }
+            atomic.AddInt64(&n_hits, got)
+        }
+    })
+
+    for _, err := range errs {
+        if err != nil {
+            return nil, 0, err
+        }
+    }
+    counts := parts[0]
+    for _, p := range parts[1:] {
+        for i, c := range p {
+            counts[i] += c
+        }
+    }
+    if n_iter > max_iter {
+        n_iter = max_iter
+    }
+    return counts, n_iter, nil
+}
+
+// ParamDensity is the bifurcation diagram of a family of 2D maps
+// as a histogram, like Density: every one of width columns covers
+// p in [p_lo, p_hi], iterates family(p) from (x0, y0), throws away
+// the transient and bins x of the next keep iterates into height
+// rows from x_hi down. Columns where the orbit escapes stay empty
+func ParamDensity(family func(p float64) maps.Map2D, x0, y0, p_lo, p_hi, x_lo, x_hi float64, width, height, trans, keep int) []float64 {
+    counts := make([]float64, width*height)
+    dp := (p_hi - p_lo) / float64(width)
+    Pool(width, func(col int) {
+        for s := 0; s < raster_subcols; s++ {
+            m := family(p_lo + (float64(col)+(float64(s)+0.5)/raster_subcols)*dp)
+            x, y, ok := last_transient(m, x0, y0, trans)
+            if !ok {
+                continue
+            }
+            for i := 0; i < keep/raster_subcols; i++ {
+                x, y = m.F(x, y)
+                if escaped(x, y) {
+                    break
+                }
+                row := int(math.Floor((x_hi - x) / (x_hi - x_lo) * float64(height)))
+                if row >= 0 && row < height {
+                    counts[row*width+col]++
+                }
+            }
+        }
+    })
+    return counts
+}
@@ -0,0 +1,76 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// the Jacobian of the Henon map has determinant -b, so the exponents
+// sum to ln b at every step; on the classic attractor the largest is
+// 0.4192 (Grassberger and Procaccia) and the Kaplan-Yorke dimension
+// 1.258
+func TestSpectrumHenon(t *testing.T) {
+    l, err := Spectrum(maps.Henon{A: 1.4, B: 0.3}, 0, 0, 1000, 1000000)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if sum := l[0] + l[1]; math.Abs(sum-math.Log(0.3)) > 1e-10 {
+        t.Errorf("lambda_1 + lambda_2 = %.14f, want ln 0.3 = %.14f", sum, math.Log(0.3))
+    }
+    if math.Abs(l[0]-0.4192) > 2e-3 {
+        t.Errorf("lambda_1 = %.6f, want 0.4192", l[0])
+    }
+    if d := KaplanYorke(l); math.Abs(d-1.258) > 5e-3 {
+        t.Errorf("Kaplan-Yorke dimension %.4f, want 1.258", d)
+    }
+}
+
+// on a stable fixed point the exponents are the logs of the
+// eigenvalues of the Jacobian there, and the dimension is 0
+func TestSpectrumFixedPoint(t *testing.T) {
+    h := maps.Henon{A: 0.2, B: 0.3}
+    x, y, ok := h.FixedPoint()
+    if !ok {
+        t.Fatal("no fixed point")
+    }
+    if fx, fy := h.F(x, y); math.Abs(fx-x) > 1e-12 || math.Abs(fy-y) > 1e-12 {
+        t.Errorf("F(%v, %v) = (%v, %v)", x, y, fx, fy)
+    }
+
+    // eigenvalues of [[-2 a x, 1], [b, 0]]
+    tr := -2 * h.A * x
+    disc := math.Sqrt(tr*tr + 4*h.B)
+    want := [2]float64{math.Log(math.Abs(tr+disc) / 2), math.Log(math.Abs(tr-disc) / 2)}
+    if want[0] < want[1] {
+        want[0], want[1] = want[1], want[0]
+    }
+
+    l, err := Spectrum(h, 0, 0, 1000, 10000)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for i := range l {
+        if math.Abs(l[i]-want[i]) > 1e-3 {
+            t.Errorf("lambda_%v = %.6f, want %.6f", i+1, l[i], want[i])
+        }
+    }
+    if d := KaplanYorke(l); d != 0 {
+        t.Errorf("Kaplan-Yorke dimension %v, want 0", d)
+    }
+}
+
+func TestOrbit2DEscapes(t *testing.T) {
+    h := maps.Henon{A: 1.4, B: 0.3}
+    if _, _, err := Orbit2D(h, 10, 10, 10, 10); err == nil {
+        t.Error("no error for an orbit that escapes")
+    }
+    if _, err := Spectrum(h, 10, 10, 10, 10); err == nil {
+        t.Error("no error for a spectrum from an orbit that escapes")
+    }
+    xs, ys, err := Orbit2D(h, 0, 0, 1000, 100)
+    if err != nil || len(xs) != 100 || len(ys) != 100 {
+        t.Errorf("%v points and error %v on the attractor", len(xs), err)
+    }
+}
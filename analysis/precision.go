@@ -0,0 +1,65 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+    "math/big"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// PrecisionRun is an orbit followed in float64 (X) and with
+// big.Float at Prec bits (Big, also as Text). Diff is their
+// difference and DiffBig the difference between the Prec-bit run
+// and one at 2*Prec bits. Split is the first n where Diff is above
+// the tolerance and Lost the first where DiffBig is (-1 if never)
+type PrecisionRun struct {
+    Prec uint
+    X, Big, Diff, DiffBig []float64
+    Text []string
+    Split, Lost int
+}
+
+// Precision follows (r, x0) for n iterations in float64 and with
+// big.Float at prec and 2*prec bits. It returns an error if the map
+// has no high-precision version
+func Precision(m maps.Map1D, r, x0 float64, n int, prec uint, tol float64) (PrecisionRun, error) {
+    bm, ok := m.(maps.BigMap1D)
+    if !ok {
+        return PrecisionRun{}, fmt.Errorf("this map has no high-precision version")
+    }
+
+    x := x0
+    rb := new(big.Float).SetPrec(prec).SetFloat64(r)
+    rb2 := new(big.Float).SetPrec(2 * prec).SetFloat64(r)
+    xb := new(big.Float).SetPrec(prec).SetFloat64(x0)
+    xb2 := new(big.Float).SetPrec(2 * prec).SetFloat64(x0)
+
+    // digits worth printing for the big trajectory
+    digits := int(float64(prec) * math.Log10(2))
+    if digits > 30 {
+        digits = 30
+    }
+
+    run := PrecisionRun{Prec: prec, Split: -1, Lost: -1}
+    for i := 0; i <= n; i++ {
+        exact, _ := xb.Float64()
+        diff := math.Abs(x - exact)
+        diff2, _ := new(big.Float).SetPrec(2 * prec).Sub(xb, xb2).Float64()
+        run.X = append(run.X, x)
+        run.Big = append(run.Big, exact)
+        run.Text = append(run.Text, xb.Text('f', digits))
+        run.Diff = append(run.Diff, diff)
+        run.DiffBig = append(run.DiffBig, math.Abs(diff2))
+        if run.Split < 0 && diff > tol {
+            run.Split = i
+        }
+        if run.Lost < 0 && math.Abs(diff2) > tol {
+            run.Lost = i
+        }
+        x = m.F(r, x)
+        xb = bm.FBig(rb, xb)
+        xb2 = bm.FBig(rb2, xb2)
+    }
+    return run, nil
+}
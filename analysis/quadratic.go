@@ -0,0 +1,81 @@
+package analysis
+
+import (
+    "math"
+    "math/cmplx"
+)
+
+// EscapeRadius is where an orbit of z^2 + c counts as escaped
+const EscapeRadius = 1e3
+
+// QuadraticC is the parameter c = r/2 - r^2/4 of the quadratic
+// family z^2 + c conjugate to the logistic map at r, through
+// z = r (1/2 - x)
+func QuadraticC(r float64) float64 {
+    return r/2 - r*r/4
+}
+
+// LogisticR is the inverse of QuadraticC on r >= 1, defined for
+// c <= 1/4
+func LogisticR(c float64) float64 {
+    return 1 + math.Sqrt(1-4*c)
+}
+
+// EscapeTime iterates z^2 + c from z for up to n_max steps and
+// returns the smoothed (fractional) number of steps it took |z| to
+// pass EscapeRadius, or -1 if it never did
+func EscapeTime(z, c complex128, n_max int) float64 {
+    for n := 0; n < n_max; n++ {
+        if a := cmplx.Abs(z); a > EscapeRadius {
+            return float64(n) + 1 - math.Log2(math.Log(a))
+        }
+        z = z*z + c
+    }
+    return -1
+}
+
+// escape times for every pixel of a width x height raster, row by
+// row from im_hi down, where at(re, im) gives the (z0, c) to iterate
+func escape_raster(re_lo, re_hi, im_lo, im_hi float64, width, height, n_max int, at func(re, im float64) (complex128, complex128)) []float64 {
+    times := make([]float64, width*height)
+    d_re := (re_hi - re_lo) / float64(width)
+    d_im := (im_hi - im_lo) / float64(height)
+    Pool(height, func(row int) {
+        im := im_hi - (float64(row)+0.5)*d_im
+        for col := 0; col < width; col++ {
+            z, c := at(re_lo+(float64(col)+0.5)*d_re, im)
+            times[row*width+col] = EscapeTime(z, c, n_max)
+        }
+    })
+    return times
+}
+
+// Mandelbrot returns the escape time of 0 under z^2 + c for c on
+// a width x height raster of [re_lo, re_hi] x [im_lo, im_hi],
+// row by row from im_hi down (-1 inside the set)
+func Mandelbrot(re_lo, re_hi, im_lo, im_hi float64, width, height, n_max int) []float64 {
+    return escape_raster(re_lo, re_hi, im_lo, im_hi, width, height, n_max, func(re, im float64) (complex128, complex128) {
+        return 0, complex(re, im)
+    })
+}
+
+// Julia returns the escape time of every z on a width x height
+// raster of [re_lo, re_hi] x [im_lo, im_hi] under z^2 + c, row by
+// row from im_hi down (-1 in the filled Julia set)
+func Julia(c complex128, re_lo, re_hi, im_lo, im_hi float64, width, height, n_max int) []float64 {
+    return escape_raster(re_lo, re_hi, im_lo, im_hi, width, height, n_max, func(re, im float64) (complex128, complex128) {
+        return complex(re, im), c
+    })
+}
+
+// MandelbrotStrip is the Mandelbrot set around the real axis drawn
+// against r instead of c: column i of width covers r in
+// [r_lo, r_hi] and shows c = QuadraticC(r) + i y for y in
+// [im_lo, im_hi]. Its columns line up with those of Density, so
+// each bulb sits over the periodic window of the logistic map that
+// it corresponds to
+func MandelbrotStrip(r_lo, r_hi, im_lo, im_hi float64, width, height, n_max int) []float64 {
+    return escape_raster(r_lo, r_hi, im_lo, im_hi, width, height, n_max, func(r, im float64) (complex128, complex128) {
+        return 0, complex(QuadraticC(r), im)
+    })
+}
@@ -0,0 +1,148 @@
+package analysis
+
+import (
+    "math"
+    "sync/atomic"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// distances from the attractor the decay of |x_n - x*| is fitted
+// between: close enough to be linear, far enough from round-off.
+// Critical slowing down near a bifurcation needs up to fit_iter
+// iterations to get through that range
+const (
+    fit_hi   = 1e-3
+    fit_lo   = 1e-10
+    fit_iter = 200000
+)
+
+// RateFit is a fitted decay rate: the slope of ln|x_n - x*| per
+// iteration, its standard error and the iterations it was fitted on
+type RateFit struct {
+    Rate, Err         float64
+    First, Last, NPts int
+}
+
+// DecayRate fits the exponential decay of |x_n - x*| towards the
+// attractor, starting from x0. Only every p-th iterate is used so
+// the distances all belong to the same cycle point, which makes
+// ln|x_n - x*| a straight line of slope (1/p) ln|(f^p)'(x*)| once
+// the orbit is close. It reports whether enough points were
+// available
+func DecayRate(m maps.Map1D, r, x0 float64, att Attractor) (RateFit, bool) {
+    p := len(att.Cycle)
+    var ns, ls []float64
+    first := -1
+    x := x0
+    for i := 0; i < fit_iter; i++ {
+        dist := math.Abs(x - att.Nearest(x))
+        if dist < fit_lo {
+            break
+        }
+        if first < 0 && dist < fit_hi {
+            first = i
+        }
+        if first >= 0 && (i-first)%p == 0 {
+            ns = append(ns, float64(i))
+            ls = append(ls, math.Log(dist))
+        }
+        x = m.F(r, x)
+    }
+    if len(ns) < 3 {
+        return RateFit{}, false
+    }
+    slope, _, err := FitLine(ns, ls)
+    return RateFit{Rate: slope, Err: err, First: first, Last: int(ns[len(ns)-1]), NPts: len(ns)}, true
+}
+
+// FitLine is a least squares fit y = slope*x + icept. It returns
+// the slope, the intercept and the standard error of the slope
+func FitLine(xs, ys []float64) (float64, float64, float64) {
+    n := float64(len(xs))
+    var sx, sy float64
+    for i := range xs {
+        sx += xs[i]
+        sy += ys[i]
+    }
+    mx, my := sx/n, sy/n
+    var sxx, sxy float64
+    for i := range xs {
+        sxx += (xs[i] - mx) * (xs[i] - mx)
+        sxy += (xs[i] - mx) * (ys[i] - my)
+    }
+    slope := sxy / sxx
+    icept := my - slope*mx
+    if len(xs) < 3 {
+        return slope, icept, 0
+    }
+    var ss float64
+    for i := range xs {
+        res := ys[i] - slope*xs[i] - icept
+        ss += res * res
+    }
+    return slope, icept, math.Sqrt(ss / (n - 2) / sxx)
+}
+
+// Rates compares how fast orbits from x0 converge with the rate the
+// cycle multiplier predicts, (1/p) ln|(f^p)'(x*)|, for every r in
+// [r_lo, r_hi] with step dr. It returns r, the predicted rates and
+// the fitted ones (NaN where there's no cycle or no fit; both are
+// held above LiapFloor)
+func (g *Grid) Rates(x0, r_lo, r_hi, dr float64, trans, pmax int) ([]float64, []float64, []float64) {
+    n_pts := window_steps(r_lo, r_hi, dr)
+    rs := make([]float64, n_pts)
+    preds := make([]float64, n_pts)
+    fits := make([]float64, n_pts)
+    g.Sweep(n_pts, func(i int) {
+        r := r_lo + float64(i)*dr
+        rs[i], preds[i], fits[i] = r, math.NaN(), math.NaN()
+        att, ok := FindAttractor(g.Map, r, x0, trans, pmax)
+        if !ok {
+            return
+        }
+        preds[i] = math.Max(math.Log(math.Abs(att.Mult))/float64(len(att.Cycle)), LiapFloor)
+        if fit, ok := DecayRate(g.Map, r, x0, att); ok && fit.Rate > LiapFloor {
+            fits[i] = fit.Rate
+        }
+    })
+    return rs, preds, fits
+}
+
+// SettleTime counts the iterations a grid cell takes to get within
+// tol of the attractor, giving up after n_max (cells that are
+// caught by some other attractor never arrive)
+func (g *Grid) SettleTime(ir, ix int, att Attractor, tol float64, n_max int) int {
+    atomic.AddInt64(&g.Stats.Cells, 1)
+    r, x := g.R(ir), g.X(ix)
+    n := 0
+    for ; n < n_max && math.Abs(x-att.Nearest(x)) > tol; n++ {
+        x = g.Map.F(r, x)
+    }
+    atomic.AddInt64(&g.Stats.Iters, int64(n))
+    return n
+}
+
+// Transients returns how long every (r, x0) cell takes to get
+// within tol of the attractor found from x0, indexed by
+// ir*NX + ix. Cells whose r has no attracting cycle (chaos) are NaN
+func (g *Grid) Transients(x0 float64, trans, pmax int, tol float64) []float64 {
+    // one attractor per r
+    atts := make([]Attractor, g.NR)
+    found := make([]bool, g.NR)
+    g.Sweep(len(atts), func(i int) {
+        atts[i], found[i] = FindAttractor(g.Map, g.R(i), x0, trans, pmax)
+    })
+
+    // then the time every cell takes to reach it
+    z := make([]float64, g.NR*g.NX)
+    g.Sweep(len(z), func(i int) {
+        ir, ix := i/g.NX, i%g.NX
+        if !found[ir] {
+            z[i] = math.NaN()
+            return
+        }
+        z[i] = float64(g.SettleTime(ir, ix, atts[ir], tol, trans))
+    })
+    return z
+}
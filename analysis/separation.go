@@ -0,0 +1,92 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// SatFrac is the separation (as a fraction of the attractor's
+// width) at which two nearby orbits count as decorrelated
+const SatFrac = 0.1
+
+// Separation follows two orbits started eps apart on the attractor:
+// X0 is the initial condition, Start where its orbit is after the
+// transient and XP the perturbed copy of Start, Xs and XPs the two
+// orbits from there and Deltas their distance |Xn - Xn'|. Sat is the
+// first n where the separation is above SatFrac of the attractor's
+// Width (-1 if it never is), and when Fitted, ln|delta_n| grows like
+// Slope*n + Icept (with standard error Err) over n = 0..FitEnd
+// before that
+type Separation struct {
+    R, X0, Start, XP, Eps float64
+    Xs, XPs, Deltas []float64
+    Width float64
+    Sat int
+
+    Fitted bool
+    Slope, Icept, Err float64
+    FitEnd int
+}
+
+// Separate follows the grid cell holding (r, x0) for trans
+// iterations onto the attractor, then follows it and a copy
+// perturbed by eps (towards the inside of the x window) for n
+// iterations, and fits the exponential growth of their separation
+// before it saturates. Perturbing x0 itself would be no good when it
+// is the critical point of a symmetric map, where f(c + eps) =
+// f(c - eps) puts both orbits on the same point after one step
+func (g *Grid) Separate(r, x0, eps float64, trans, n int) (Separation, error) {
+    s := Separation{R: g.R(g.RIndex(r)), X0: g.X(g.XIndex(x0)), Eps: eps, Sat: -1}
+
+    g.Timed(func() {
+        s.Start = maps.Gen(g.Map, s.R, s.X0, trans, 1)[0]
+    })
+    if math.IsNaN(s.Start) || math.IsInf(s.Start, 0) {
+        return s, fmt.Errorf("orbit of x0 = %v blew up at r = %v", s.X0, s.R)
+    }
+    x_lo, x_hi := g.Map.XRange()
+    s.XP = s.Start + eps
+    if s.XP > x_hi || s.XP > g.X(g.NX) {
+        s.XP = s.Start - eps
+    }
+    if s.XP < x_lo {
+        return s, fmt.Errorf("eps = %v doesn't fit inside the x range", eps)
+    }
+
+    g.Timed(func() {
+        s.Xs, s.XPs = maps.Gen(g.Map, s.R, s.Start, 0, n), maps.Gen(g.Map, s.R, s.XP, 0, n)
+    })
+    lo, hi := s.Xs[0], s.Xs[0]
+    for _, x := range s.Xs {
+        lo, hi = math.Min(lo, x), math.Max(hi, x)
+    }
+    s.Width = hi - lo
+
+    // saturation is the first time the separation is comparable
+    // with the attractor itself
+    var ns, ls []float64
+    s.Deltas = make([]float64, n)
+    for i := range s.Xs {
+        delta := math.Abs(s.Xs[i] - s.XPs[i])
+        s.Deltas[i] = delta
+        if delta == 0 {
+            continue
+        }
+        if s.Sat < 0 && delta > SatFrac*s.Width {
+            s.Sat = i
+        }
+        if s.Sat < 0 {
+            ns = append(ns, float64(i))
+            ls = append(ls, math.Log(delta))
+        }
+    }
+
+    if len(ns) >= 3 {
+        s.Fitted = true
+        s.Slope, s.Icept, s.Err = FitLine(ns, ls)
+        s.FitEnd = int(ns[len(ns)-1])
+    }
+    return s, nil
+}
@@ -0,0 +1,49 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// at r = 4 the logistic map has Liapunov exponent ln 2, which the
+// growth of the separation should match (x0 = 1/2 is no good here,
+// its orbit lands on the fixed point at 0)
+func TestSeparateGrowth(t *testing.T) {
+    m := maps.Logistic{}
+    g, err := NewGrid(m, 3.5, 4.5, 0, 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, x0 := range []float64{0.1, 0.3, 0.7} {
+        liap := Exponent(m, 4, x0, 1000, 100000)
+        if math.Abs(liap-math.Ln2) > 1e-3 {
+            t.Errorf("x0 = %v: Liapunov exponent %.6f, want ln 2", x0, liap)
+        }
+        s, err := g.Separate(4, x0, 1e-12, 1000, 100)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if !s.Fitted || math.Abs(s.Slope-liap) > 0.05 {
+            t.Errorf("x0 = %v: fitted %.4f +/- %.4f (fitted %v), want %.4f within 0.05", x0, s.Slope, s.Err, s.Fitted, liap)
+        }
+    }
+}
+
+// started from the critical point of the (symmetric) logistic map the
+// two orbits must still separate
+func TestSeparateFromCrit(t *testing.T) {
+    m := maps.Logistic{}
+    g, err := NewGrid(m, 0, 4, 0, 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    s, err := g.Separate(3.9, m.Crit(3.9), 1e-10, 2000, 100)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !s.Fitted || s.Sat < 0 || s.Slope <= 0 {
+        t.Errorf("orbits from x0 = 1/2 at r = 3.9: fitted %v, slope %v, saturation at %v", s.Fitted, s.Slope, s.Sat)
+    }
+}
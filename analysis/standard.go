@@ -0,0 +1,218 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// TorusOrbit is one orbit of the standard map: where it started,
+// the points it visited, its largest Liapunov exponent over them
+// and whether that makes it part of the chaotic sea
+type TorusOrbit struct {
+    Theta0, P0 float64
+    Thetas, Ps []float64
+    Lambda     float64
+    Chaotic    bool
+}
+
+// the start of orbit i of an n_side x n_side grid over the torus
+func torus_start(i, n_side int) (float64, float64) {
+    theta := (float64(i/n_side) + 0.5) / float64(n_side) * 2 * math.Pi
+    p := -math.Pi + (float64(i%n_side)+0.5)/float64(n_side)*2*math.Pi
+    return theta, p
+}
+
+// Portrait iterates m n steps from each of an n_side x n_side grid
+// of starting points over the torus. An orbit counts as chaotic when
+// its largest Liapunov exponent over those n steps is above
+// lambda_c, since on a KAM torus or island it only falls off like
+// ln(n)/n. Orbits are returned in order of theta0 and then p0
+func Portrait(m maps.Standard, n_side, n int, lambda_c float64) ([]TorusOrbit, error) {
+    orbits := make([]TorusOrbit, n_side*n_side)
+    errs := make([]error, len(orbits))
+    Pool(len(orbits), func(i int) {
+        theta, p := torus_start(i, n_side)
+        thetas, ps, err := Orbit2D(m, theta, p, 0, n)
+        if err != nil {
+            errs[i] = err
+            return
+        }
+        l, err := Spectrum(m, theta, p, 0, n)
+        if err != nil {
+            errs[i] = err
+            return
+        }
+        orbits[i] = TorusOrbit{Theta0: theta, P0: p, Thetas: thetas, Ps: ps, Lambda: l[0], Chaotic: l[0] > lambda_c}
+    })
+    for _, err := range errs {
+        if err != nil {
+            return nil, err
+        }
+    }
+    return orbits, nil
+}
+
+// SeaFraction is the fraction of the orbits that are chaotic, which
+// for orbits started on an even grid estimates the share of the
+// torus taken up by the chaotic sea
+func SeaFraction(orbits []TorusOrbit) float64 {
+    if len(orbits) == 0 {
+        return 0
+    }
+    n := 0
+    for _, o := range orbits {
+        if o.Chaotic {
+            n++
+        }
+    }
+    return float64(n) / float64(len(orbits))
+}
+
+// SeaCurve is SeaFraction for each K in ks, from the Liapunov
+// exponents alone (no orbits are kept)
+func SeaCurve(ks []float64, n_side, n int, lambda_c float64) ([]float64, error) {
+    n_orb := n_side * n_side
+    chaotic := make([]bool, len(ks)*n_orb)
+    errs := make([]error, len(chaotic))
+    Pool(len(chaotic), func(i int) {
+        theta, p := torus_start(i%n_orb, n_side)
+        l, err := Spectrum(maps.Standard{K: ks[i/n_orb]}, theta, p, 0, n)
+        errs[i] = err
+        chaotic[i] = l[0] > lambda_c
+    })
+
+    fracs := make([]float64, len(ks))
+    for i, c := range chaotic {
+        if errs[i] != nil {
+            return nil, errs[i]
+        }
+        if c {
+            fracs[i/n_orb] += 1 / float64(n_orb)
+        }
+    }
+    return fracs, nil
+}
+
+// Approximant is one step of Greene's residue criterion for the
+// golden mean torus of the standard map: the symmetric elliptic orbit
+// of winding number M/Q (successive Fibonacci numbers, so M/Q tends
+// to the golden mean) and the K at which its residue reaches 1/4.
+// The K of successive approximants converge on the K at which the
+// torus breaks
+type Approximant struct {
+    M, Q int
+    K    float64
+}
+
+// the residual of the symmetry condition for the orbit of winding
+// m/q from (pi, p0) on the lifted map, and its derivative in p0. An
+// orbit starting on the symmetry line theta = pi is periodic when
+// it reaches theta = pi (mod pi) after q/2 steps for even q, or the
+// line theta = p/2 (mod pi) after (q+1)/2 steps for odd q
+func symmetry_residual(k, p0 float64, m, q int) (float64, float64) {
+    theta, p := math.Pi, p0
+    d_theta, d_p := 0., 1.
+    steps := (q + 1) / 2
+    for i := 0; i < steps; i++ {
+        d_p += k * math.Cos(theta) * d_theta
+        p += k * math.Sin(theta)
+        d_theta += d_p
+        theta += p
+    }
+    if q%2 == 0 {
+        return theta - math.Pi*float64(1+m), d_theta
+    }
+    return theta - p/2 - math.Pi*float64(1+m), d_theta - d_p/2
+}
+
+// Newton's method for the p0 of the symmetric m/q orbit at K = k,
+// starting from the guess p0
+func symmetric_newton(k, p0 float64, m, q int) (float64, bool) {
+    for it := 0; it < 30; it++ {
+        g, dg := symmetry_residual(k, p0, m, q)
+        step := g / dg
+        if math.IsNaN(step) || math.Abs(step) > 0.1 {
+            return p0, false
+        }
+        p0 -= step
+        if math.Abs(step) < 1e-13 {
+            return p0, true
+        }
+    }
+    return p0, false
+}
+
+// SymmetricOrbit finds the p0 of the elliptic orbit of winding
+// number m/q through (pi, p0) at K = k by following it from K = 0
+// (where p0 = 2pi m/q), halving the steps in K whenever Newton fails
+// to hold on to it. It reports whether the orbit could be followed
+func SymmetricOrbit(k float64, m, q int) (float64, bool) {
+    p0 := 2 * math.Pi * float64(m) / float64(q)
+    at, dk := 0., k/20
+    for at < k {
+        next := math.Min(k, at+dk)
+        p, ok := symmetric_newton(next, p0, m, q)
+        if !ok {
+            if dk /= 2; dk < 1e-12 {
+                return p0, false
+            }
+            continue
+        }
+        p0, at = p, next
+        dk *= 1.5
+    }
+    return p0, true
+}
+
+// Residue is Greene's residue (2 - trace M)/4 of the q-cycle of the
+// standard map at K = k through (theta, p), with M the product of
+// the Jacobians around it. The cycle is stable for 0 < R < 1
+func Residue(k, theta, p float64, q int) float64 {
+    m := maps.Standard{K: k}
+    prod := [2][2]float64{{1, 0}, {0, 1}}
+    for i := 0; i < q; i++ {
+        j := m.Jacobian(theta, p)
+        prod = [2][2]float64{
+            {j[0][0]*prod[0][0] + j[0][1]*prod[1][0], j[0][0]*prod[0][1] + j[0][1]*prod[1][1]},
+            {j[1][0]*prod[0][0] + j[1][1]*prod[1][0], j[1][0]*prod[0][1] + j[1][1]*prod[1][1]}}
+        theta, p = m.Lift(theta, p)
+    }
+    return (2 - prod[0][0] - prod[1][1]) / 4
+}
+
+// LastTorus applies Greene's residue criterion for the K at which
+// the last (golden mean) KAM torus breaks: for each Fibonacci
+// winding number M/Q with Q up to q_max it bisects [k_lo, k_hi]
+// down to tol for the K where the residue of the symmetric elliptic
+// M/Q orbit reaches 1/4 (an orbit that can't be followed counts as
+// past the break). The K converge geometrically, so the sequence
+// stops early at an approximant that moves further from the one
+// before than that one did, which is where round-off has taken over
+// from the long orbits. It returns an error if fewer than two
+// approximants could be found
+func LastTorus(k_lo, k_hi float64, q_max int, tol float64) ([]Approximant, error) {
+    var apps []Approximant
+    for m, q := 3, 5; q <= q_max; m, q = q, m+q {
+        lo, hi := k_lo, k_hi
+        for hi-lo > tol {
+            mid := (lo + hi) / 2
+            p0, ok := SymmetricOrbit(mid, m, q)
+            if !ok || Residue(mid, math.Pi, p0, q) > 0.25 {
+                hi = mid
+            } else {
+                lo = mid
+            }
+        }
+        app := Approximant{M: m, Q: q, K: (lo + hi) / 2}
+        if n := len(apps); n >= 2 && math.Abs(app.K-apps[n-1].K) > math.Abs(apps[n-1].K-apps[n-2].K) {
+            break
+        }
+        apps = append(apps, app)
+    }
+    if len(apps) < 2 {
+        return apps, fmt.Errorf("need at least two approximants with q <= %v between K = %v and %v, found %v", q_max, k_lo, k_hi, len(apps))
+    }
+    return apps, nil
+}
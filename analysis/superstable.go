@@ -0,0 +1,195 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+//////////////////////////////////////////////////////////////
+// Purpose: Evaluate g(r) = f^p(c) - c for the critical     //
+// point c, along with dg/dr (x and dx/dr are carried       //
+// through the iteration; df/dr and dc/dr are taken by      //
+// central differences)                                     //
+// Return: g(r) and dg/dr                                   //
+//////////////////////////////////////////////////////////////
+func superstable_g(m maps.Map1D, r float64, p int) (float64, float64) {
+    h := 1e-6 * math.Max(1, math.Abs(r))
+    c := m.Crit(r)
+    dc := (m.Crit(r+h) - m.Crit(r-h)) / (2 * h)
+    x, dx := c, dc
+    for k := 0; k < p; k++ {
+        dfdr := (m.F(r+h, x) - m.F(r-h, x)) / (2 * h)
+        x, dx = m.F(r, x), m.DF(r, x)*dx+dfdr
+    }
+    return x - c, dx - dc
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Newton's method in r for f^p(c) = c starting    //
+// from the guess r                                         //
+// Return: The root, the size of the last Newton step and   //
+// whether it converged                                     //
+//////////////////////////////////////////////////////////////
+func superstable_newton(m maps.Map1D, r float64, p int) (float64, float64, bool) {
+    step := math.Inf(1)
+    for it := 0; it < 60; it++ {
+        g, dg := superstable_g(m, r, p)
+        if dg == 0 || math.IsNaN(g) {
+            return r, step, false
+        }
+        step = g / dg
+        r -= step
+        if math.Abs(step) < 1e-15*math.Max(1, math.Abs(r)) {
+            return r, math.Abs(step), true
+        }
+    }
+    // round-off can stop the last few digits settling, which
+    // is fine as long as the steps got small
+    return r, math.Abs(step), math.Abs(step) < 1e-12
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Find the first sign change of f^p(c) - c in     //
+// (lo, hi] on a scan of the given number of steps, and     //
+// converge onto the root it brackets (bisecting, then      //
+// polishing with Newton as long as it stays in the         //
+// bracket)                                                 //
+// Return: The root, the last step and whether one was      //
+// found                                                    //
+//////////////////////////////////////////////////////////////
+func superstable_scan(m maps.Map1D, lo, hi float64, steps, p int) (float64, float64, bool) {
+    dr := (hi - lo) / float64(steps)
+    g_prev, _ := superstable_g(m, lo, p)
+    for i := 1; i <= steps; i++ {
+        r := lo + float64(i)*dr
+        g, _ := superstable_g(m, r, p)
+        if g == 0 || (g < 0) != (g_prev < 0) {
+            a, b := r-dr, r
+            for j := 0; j < 30; j++ {
+                mid := (a + b) / 2
+                g_mid, _ := superstable_g(m, mid, p)
+                if (g_mid < 0) == (g_prev < 0) {
+                    a = mid
+                } else {
+                    b = mid
+                }
+            }
+            if root, step, ok := superstable_newton(m, (a+b)/2, p); ok && root >= r-dr && root <= r {
+                return root, step, true
+            }
+            return (a + b) / 2, b - a, true
+        }
+        g_prev = g
+    }
+    return 0, 0, false
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: superstable_scan over [lo, hi], taking an end   //
+// of the range that is itself a root (R_0 = -1 for the     //
+// Gauss map) before scanning the inside                    //
+// Return: The root, the last Newton step and whether one   //
+// was found                                                //
+//////////////////////////////////////////////////////////////
+func superstable_range(m maps.Map1D, lo, hi float64, p int) (float64, float64, bool) {
+    for _, r := range []float64{lo, hi} {
+        if g, _ := superstable_g(m, r, p); math.Abs(g) <= 1e-14*math.Max(1, math.Abs(m.Crit(r))) {
+            return r, 0, true
+        }
+    }
+    // keep clear of the range ends where some maps degenerate
+    pad := 1e-9 * (hi - lo)
+    return superstable_scan(m, lo+pad, hi-pad, 2000, p)
+}
+
+// Superstable solves for the superstable parameters R_n, where the
+// critical point lies on the 2^n-cycle. R_0 and R_1 come from
+// scanning the map's r range. Every later R_n is the first root of
+// f^(2^n)(c) = c past R_n-1 (the only superstable parameters before
+// the onset of chaos are the R_n, so no other root of it lies in
+// between): it is bracketed by a scan in steps of a hundredth of the
+// last gap and converged onto, and must lie within that gap of
+// R_n-1. It returns R_0..R_n_max (fewer if the solver runs out of
+// precision) and the last step taken for each, or an error if not
+// even R_0 and R_1 exist
+func Superstable(m maps.Map1D, n_max int) ([]float64, []float64, error) {
+    r_lo, r_hi := m.RRange()
+
+    r0, step0, ok := superstable_range(m, r_lo, r_hi, 1)
+    if !ok {
+        return nil, nil, fmt.Errorf("no superstable fixed point in [%v, %v]", r_lo, r_hi)
+    }
+    // f^2(c) = c also holds at R_0, so start just past it
+    r1, step1, ok := superstable_range(m, r0+1e-6*(r_hi-r_lo), r_hi, 2)
+    if !ok {
+        return nil, nil, fmt.Errorf("no superstable 2-cycle after R_0 = %v", r0)
+    }
+    rs := []float64{r0, r1}
+    errs := []float64{step0, step1}
+
+    for n := 2; n <= n_max; n++ {
+        // the root at R_n-1 itself is simple, so start just clear of it
+        gap := rs[n-1] - rs[n-2]
+        lo, hi := rs[n-1]+1e-3*gap, math.Min(rs[n-1]+gap, r_hi)
+        if lo >= hi || lo == rs[n-1] {
+            break
+        }
+        r, step, ok := superstable_scan(m, lo, hi, 100, 1<<uint(n))
+        if !ok {
+            break
+        }
+        rs = append(rs, r)
+        errs = append(errs, step)
+    }
+    return rs, errs, nil
+}
+
+// Widths returns d_n = f^(2^(n-1))(c) - c at each R_n, the distance
+// from the critical point to its nearest neighbour on the cycle
+// (d_0 is left at 0)
+func Widths(m maps.Map1D, rs []float64) []float64 {
+    ds := make([]float64, len(rs))
+    for n, r := range rs {
+        if n == 0 {
+            continue
+        }
+        c := m.Crit(r)
+        x := c
+        for k := 0; k < 1<<uint(n-1); k++ {
+            x = m.F(r, x)
+        }
+        ds[n] = x - c
+    }
+    return ds
+}
+
+// Window is a frame of the zoom sequence around R_n, with D the
+// distance d_n from the critical point C to the cycle
+type Window struct {
+    N        int
+    R, C, D  float64
+    RLo, RHi float64
+    XLo, XHi float64
+}
+
+// ZoomWindows lays out nested windows around the superstable points
+// R_1, R_2, ... to show the self-similarity of the cascade. Window n
+// spans half the gap R_n - R_n-1 either side of R_n (so it shrinks
+// by delta each time) and runs from half of d_n below the critical
+// point to 1.5 d_n above (so it shrinks by alpha). Windows with
+// D < 0 are upside down compared with the others
+func ZoomWindows(m maps.Map1D, rs []float64) []Window {
+    ds := Widths(m, rs)
+    wins := make([]Window, 0, len(rs))
+    for n := 1; n < len(rs); n++ {
+        r, d_n := rs[n], ds[n]
+        c := m.Crit(r)
+        span := rs[n] - rs[n-1]
+        wins = append(wins, Window{N: n, R: r, C: c, D: d_n,
+            RLo: r - span/2, RHi: r + span/2,
+            XLo: math.Min(c-d_n/2, c+1.5*d_n), XHi: math.Max(c-d_n/2, c+1.5*d_n)})
+    }
+    return wins
+}
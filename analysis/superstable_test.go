@@ -0,0 +1,87 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "github.com/tmitchel/chaos/maps"
+)
+
+// superstable parameters of the logistic map, R_1 = 1 + sqrt(5) and
+// the rest from the published tables
+var logistic_superstable = []float64{
+    2,
+    1 + math.Sqrt(5),
+    3.498561699327702,
+    3.554640862768825,
+    3.566667379856269,
+    3.569243531637110,
+    3.569795293749944,
+    3.569913465422348,
+    3.569938774233305,
+}
+
+func TestSuperstableLogistic(t *testing.T) {
+    rs, _, err := Superstable(maps.Logistic{}, len(logistic_superstable)-1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(rs) < len(logistic_superstable) {
+        t.Fatalf("found %v superstable parameters, want %v", len(rs), len(logistic_superstable))
+    }
+    for n, want := range logistic_superstable {
+        if math.Abs(rs[n]-want) > 1e-12 {
+            t.Errorf("R_%d = %.16f, want %.16f", n, rs[n], want)
+        }
+    }
+}
+
+// the R_n of other families must be the superstable points of the
+// cascade itself: increasing, each with the critical point on the
+// 2^n-cycle and on the opposite side of its neighbour to the last
+// one, with the gaps shrinking by the delta of the family's maximum
+// (quadratic for cubic and sine, quartic for quartic)
+func TestSuperstableFamilies(t *testing.T) {
+    const n_max = 10
+    for _, c := range []struct {
+        name  string
+        delta float64
+    }{{"cubic", Delta}, {"sine", Delta}, {"quartic", 7.284686217}} {
+        m := maps.Family[c.name]
+        rs, _, err := Superstable(m, n_max)
+        if err != nil {
+            t.Fatalf("%s: %v", c.name, err)
+        }
+        if len(rs) < n_max+1 {
+            t.Fatalf("%s: found %v superstable parameters, want %v", c.name, len(rs), n_max+1)
+        }
+        ds := Widths(m, rs)
+        for n := 1; n <= n_max; n++ {
+            if rs[n] <= rs[n-1] {
+                t.Errorf("%s: R_%d = %v not past R_%d = %v", c.name, n, rs[n], n-1, rs[n-1])
+            }
+            if g, _ := superstable_g(m, rs[n], 1<<uint(n)); math.Abs(g) > 1e-9 {
+                t.Errorf("%s: f^%d(c) - c = %v at R_%d", c.name, 1<<uint(n), g, n)
+            }
+            if n >= 2 && (ds[n] < 0) == (ds[n-1] < 0) {
+                t.Errorf("%s: d_%d = %v and d_%d = %v have the same sign", c.name, n-1, ds[n-1], n, ds[n])
+            }
+        }
+        delta := (rs[n_max-1] - rs[n_max-2]) / (rs[n_max] - rs[n_max-1])
+        if math.Abs(delta-c.delta) > 1e-3 {
+            t.Errorf("%s: delta_%d = %.6f, want %.6f within 1e-3", c.name, n_max, delta, c.delta)
+        }
+    }
+}
+
+// R_2 of the quartic map, the first root of f^4(c) = c past R_1
+// (found to 50 digits by bisection)
+func TestSuperstableQuarticR2(t *testing.T) {
+    rs, _, err := Superstable(maps.Quartic{}, 2)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := 0.96078124940803411; len(rs) < 3 || math.Abs(rs[2]-want) > 1e-12 {
+        t.Errorf("R_2 = %v, want %.17f", rs, want)
+    }
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+    "log"
+    "flag"
+    "strings"
+    "github.com/tmitchel/chaos/export"
+)
+
+// where a subcommand's computed data gets exported (nowhere when
+// data_out is empty)
+type export_opts struct {
+    data_out, format string
+}
+
+func (o *export_opts) flags(fs *flag.FlagSet) {
+    fs.StringVar(&o.data_out, "data", "", "Also export the computed data to this file (extension added from -format)")
+    fs.StringVar(&o.format, "format", "csv", "Format of the exported data ("+strings.Join(export.Formats, ", ")+")")
+}
+
+func (o *export_opts) check() error {
+    if !export.Valid(o.format) {
+        return invalidf("format must be one of: %s", strings.Join(export.Formats, ", "))
+    }
+    return nil
+}
+
+// an empty table with the given column names to fill for save, or
+// nil (which ignores rows added to it) when -data wasn't given, so
+// runs that don't export don't hold on to their data
+func (o *export_opts) table(names ...string) *export.Table {
+    if o.data_out == "" {
+        return nil
+    }
+    return export.NewTable(names...)
+}
+
+// export t when -data was given
+func (o *export_opts) save(t *export.Table) error {
+    if o.data_out == "" {
+        return nil
+    }
+    name, err := export.Write(o.data_out, o.format, t)
+    if err != nil {
+        return err
+    }
+    log.Printf("Data written to %s (%d rows)", name, len(t.Rows))
+    return nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+    "log"
+    "fmt"
+    "flag"
+    "math"
+    "strconv"
+    "github.com/tmitchel/chaos/flows"
+    "github.com/tmitchel/chaos/plotting"
+)
+
+/////////////////////////////////////////////////////
+// Purpose: Options shared by the flow subcommands //
+// (time span and step, the high-precision check   //
+// and where the plot and data go)                 //
+/////////////////////////////////////////////////////
+type flow_opts struct {
+    export_opts
+    t, dt, p_tol float64
+    prec uint
+    out string
+}
+
+// register the shared flow options, with def_t as the default time
+// span and out as the default plot name
+func (o *flow_opts) flags(fs *flag.FlagSet, def_t float64, out string) {
+    fs.Float64Var(&o.t, "t", def_t, "Time span to integrate over")
+    fs.Float64Var(&o.dt, "dt", 0.001, "Time step (1/n for a whole number n)")
+    fs.UintVar(&o.prec, "prec", 0, "Mantissa bits for a high-precision comparison run (0 to skip)")
+    fs.Float64Var(&o.p_tol, "ptol", 1e-6, "Difference at which the float64 and high-precision runs count as different")
+    help := "Output file for the plot"
+    if out == "" {
+        help += " (defaults to a name built from the parameters)"
+    }
+    fs.StringVar(&o.out, "plot", out, help)
+    o.export_opts.flags(fs)
+}
+
+// check the options and return the number of steps per unit time
+// and in the whole span
+func (o *flow_opts) steps() (int, int, error) {
+    per_unit := math.Round(1 / o.dt)
+    switch {
+    case o.dt <= 0 || o.dt > 1 || math.Abs(per_unit*o.dt-1) > 1e-9:
+        return 0, 0, invalidf("dt must be 1/n for a whole number n (e.g. 0.001)")
+    case o.t <= 0:
+        return 0, 0, invalidf("need t > 0")
+    case o.p_tol <= 0 || (o.prec > 0 && o.prec < 53):
+        return 0, 0, invalidf("need ptol > 0 and prec >= 53 (or 0 to skip)")
+    }
+    if err := o.check(); err != nil {
+        return 0, 0, err
+    }
+    return int(per_unit), int(math.Round(o.t * per_unit)), nil
+}
+
+// run f from start, reporting (but carrying on past) a blow-up
+func trajectory(f flows.Flow, start flows.Point, steps int) []flows.Point {
+    pts, err := flows.Run(f, start, steps)
+    if err != nil {
+        log.Printf("%v, keeping the steps before that", err)
+    }
+    return pts
+}
+
+////////////////////////////////////////////////////
+// Purpose: Run the high-precision comparison for //
+// f when -prec was given                         //
+// Returns: Nothing (prints the step where the    //
+// float64 and big trajectories first differ by   //
+// more than ptol, prefixed by label)             //
+////////////////////////////////////////////////////
+func (o *flow_opts) prec_report(label string, f flows.BigFlow, start flows.Point, steps int) {
+    if o.prec == 0 {
+        return
+    }
+    div, err := flows.Precision(f, start, steps, o.prec, o.p_tol)
+    if err != nil {
+        fmt.Printf("%sfloat64 run: %v\n", label, err)
+    }
+    if div.Split < 0 {
+        fmt.Printf("%sfloat64 and %v-bit trajectories agree to %g for all %v steps\n", label, o.prec, o.p_tol, steps)
+    } else {
+        fmt.Printf("%sfloat64 and %v-bit trajectories first disagree by more than %g at step %v (t = %v)\n", label, o.prec, o.p_tol, div.Split, float64(div.Split)*f.Dt())
+    }
+    if div.Lost < 0 {
+        fmt.Printf("%s%v-bit trajectory agrees with %v bits to %g for all %v steps\n", label, o.prec, 2*o.prec, o.p_tol, steps)
+    } else {
+        fmt.Printf("%s%v-bit trajectory agrees with %v bits to %g only up to step %v (t = %v)\n", label, o.prec, 2*o.prec, o.p_tol, div.Lost-1, float64(div.Lost-1)*f.Dt())
+    }
+}
+
+// the Rossler parameters and starting point
+type rossler_opts struct {
+    flow_opts
+    a, b, c, x0, y0, z0 float64
+}
+
+func (o *rossler_opts) flags(fs *flag.FlagSet, def_t float64, out string) {
+    fs.Float64Var(&o.a, "a", 0.2, "Parameter a")
+    fs.Float64Var(&o.b, "b", 0.2, "Parameter b")
+    fs.Float64Var(&o.c, "c", 5.7, "Parameter c")
+    fs.Float64Var(&o.x0, "x0", -1, "Initial condition x0")
+    fs.Float64Var(&o.y0, "y0", 0, "Initial condition y0")
+    fs.Float64Var(&o.z0, "z0", 0, "Initial condition z0")
+    o.flow_opts.flags(fs, def_t, out)
+}
+
+////////////////////////////////////////////////////
+// Purpose: The rossler group: the trajectory     //
+// and its Poincare section                       //
+// Return: The group                              //
+////////////////////////////////////////////////////
+func rossler_group() group {
+    return group{name: "rossler", summary: "the Rossler equations", commands: []command{
+        {name: "run", summary: "Trajectory of the Rossler system, projected onto the x-y, x-z and y-z planes", setup: func(fs *flag.FlagSet) func() error {
+            o := &rossler_opts{}
+            o.flags(fs, 100, "")
+            return func() error { return rossler_run(o) }
+        }},
+        {name: "section", summary: "Return map x_n -> x_n+1 at successive crossings of the half plane y = 0, x < 0", setup: func(fs *flag.FlagSet) func() error {
+            o := &rossler_opts{}
+            o.flags(fs, 2000, "rossler_section.pdf")
+            trans := fs.Float64("trans", 100, "Time to let the trajectory settle before recording crossings")
+            return func() error { return rossler_section(o, *trans) }
+        }},
+    }}
+}
+
+func rossler_run(o *rossler_opts) error {
+    per_unit, steps, err := o.steps()
+    if err != nil {
+        return err
+    }
+    sys := flows.Rossler{A: o.a, B: o.b, C: o.c, StepsPerUnit: per_unit}
+    start := flows.Point{X: o.x0, Y: o.y0, Z: o.z0}
+    pts := trajectory(sys, start, steps)
+
+    // save the raw numbers too
+    traj := o.table("step", "t", "x", "y", "z")
+    for i, pt := range pts {
+        traj.Add(float64(i), float64(i)/float64(per_unit), pt.X, pt.Y, pt.Z)
+    }
+    if err := o.save(traj); err != nil {
+        return err
+    }
+
+    out := o.out
+    if out == "" {
+        out = "rossler_c" + strconv.FormatFloat(o.c, 'f', -1, 64) + ".pdf"
+    }
+    if err := plotting.Rossler(sys, pts, out); err != nil {
+        return err
+    }
+
+    // compare with a high-precision run of the same steps
+    o.prec_report("", sys, start, steps)
+    return nil
+}
+
+func rossler_section(o *rossler_opts, trans float64) error {
+    per_unit, steps, err := o.steps()
+    if err != nil {
+        return err
+    }
+    if trans < 0 || trans >= o.t {
+        return invalidf("need 0 <= trans < t")
+    }
+    sys := flows.Rossler{A: o.a, B: o.b, C: o.c, StepsPerUnit: per_unit}
+    pts := trajectory(sys, flows.Point{X: o.x0, Y: o.y0, Z: o.z0}, steps)
+    skip := int(math.Round(trans * float64(per_unit)))
+    if skip > len(pts) {
+        skip = len(pts)
+    }
+
+    // y goes from positive to negative on the x < 0 side
+    cross := flows.Section(pts[skip:], func(p flows.Point) float64 { return -p.Y })
+    if len(cross) < 2 {
+        return fmt.Errorf("only %v crossings of y = 0 after t = %v, try a longer -t", len(cross), trans)
+    }
+    xs := make([]float64, len(cross))
+    table := o.table("n", "x", "y", "z")
+    for i, p := range cross {
+        xs[i] = p.X
+        table.Add(float64(i), p.X, p.Y, p.Z)
+    }
+    if err := o.save(table); err != nil {
+        return err
+    }
+    fmt.Printf("%v crossings of y = 0 between t = %v and %v\n", len(cross), trans, o.t)
+
+    title := "Rossler return map at y = 0 (c=" + strconv.FormatFloat(o.c, 'f', -1, 64) + ")"
+    return plotting.ReturnMap(xs, title, "x", o.out)
+}
+
+// the Duffing starting point (the forces belong to the subcommands)
+type duffing_opts struct {
+    flow_opts
+    x0, y0 float64
+}
+
+func (o *duffing_opts) flags(fs *flag.FlagSet, out string) {
+    fs.Float64Var(&o.x0, "x0", 0, "Initial value for x")
+    fs.Float64Var(&o.y0, "y0", 0, "Initial value for y (dx/dt)")
+    o.flow_opts.flags(fs, 100, out)
+}
+
+////////////////////////////////////////////////////
+// Purpose: The duffing group: the phase portrait //
+// for one force or two side by side              //
+// Return: The group                              //
+////////////////////////////////////////////////////
+func duffing_group() group {
+    return group{name: "duffing", summary: "the forced inverted Duffing oscillator", commands: []command{
+        {name: "run", summary: "Phase portrait (x against dx/dt) for a force F", setup: func(fs *flag.FlagSet) func() error {
+            o := &duffing_opts{}
+            F := fs.Float64("F", 0.24, "Force F")
+            o.flags(fs, "")
+            return func() error {
+                out := o.out
+                if out == "" {
+                    out = "iduff_F" + strconv.FormatFloat(*F, 'f', -1, 64) + ".pdf"
+                }
+                return duffing_run(o, []float64{*F}, out)
+            }
+        }},
+        {name: "compare", summary: "Phase portraits for two forces overlaid", setup: func(fs *flag.FlagSet) func() error {
+            o := &duffing_opts{}
+            F1 := fs.Float64("F1", 0.24, "First force")
+            F2 := fs.Float64("F2", 0.35, "Second force")
+            o.flags(fs, "iduff_comp.pdf")
+            return func() error { return duffing_run(o, []float64{*F1, *F2}, o.out) }
+        }},
+    }}
+}
+
+func duffing_run(o *duffing_opts, forces []float64, out string) error {
+    per_unit, steps, err := o.steps()
+    if err != nil {
+        return err
+    }
+    start := flows.Point{X: o.x0, Y: o.y0}
+    trajs := make([][]flows.Point, len(forces))
+    for i, f := range forces {
+        trajs[i] = trajectory(flows.Duffing{F: f, StepsPerUnit: per_unit}, start, steps)
+    }
+
+    // raw numbers, one row per step and per F
+    traj := o.table("F", "step", "t", "x", "y")
+    for i := 0; i < steps; i++ {
+        for j, f := range forces {
+            if i < len(trajs[j]) {
+                traj.Add(f, float64(i), float64(i)/float64(per_unit), trajs[j][i].X, trajs[j][i].Y)
+            }
+        }
+    }
+    if err := o.save(traj); err != nil {
+        return err
+    }
+
+    if err := plotting.Duffing(forces, trajs, out); err != nil {
+        return err
+    }
+
+    // compare with high-precision runs of the same steps
+    for _, f := range forces {
+        o.prec_report("F="+strconv.FormatFloat(f, 'f', -1, 64)+": ", flows.Duffing{F: f, StepsPerUnit: per_unit}, start, steps)
+    }
+    return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+    "fmt"
+    "flag"
+    "math"
+    "github.com/tmitchel/chaos/maps"
+    "github.com/tmitchel/chaos/export"
+    "github.com/tmitchel/chaos/analysis"
+    "github.com/tmitchel/chaos/plotting"
+)
+
+/////////////////////////////////////////////////////
+// Purpose: Options of the henon subcommands: the  //
+// map, where its orbits start and the window and  //
+// raster they're drawn on                         //
+/////////////////////////////////////////////////////
+type henon_opts struct {
+    export_opts
+    a, b, x0, y0 float64
+    trans, n int
+    win analysis.Rect
+    width, height int
+    gamma float64
+    out string
+}
+
+func (o *henon_opts) flags(fs *flag.FlagSet, n int) {
+    fs.Float64Var(&o.a, "a", 1.4, "Parameter a")
+    fs.Float64Var(&o.b, "b", 0.3, "Parameter b")
+    fs.Float64Var(&o.x0, "x0", 0, "Initial value for x")
+    fs.Float64Var(&o.y0, "y0", 0, "Initial value for y")
+    fs.IntVar(&o.trans, "trans", 1000, "Number of transient iterations to discard")
+    fs.IntVar(&o.n, "n", n, "Number of iterations to complete")
+    o.export_opts.flags(fs)
+}
+
+// the window and raster, for the subcommands drawing one
+func (o *henon_opts) raster_flags(fs *flag.FlagSet, out string) {
+    fs.Float64Var(&o.win.XLo, "xmin", -1.5, "Lowest x to show")
+    fs.Float64Var(&o.win.XHi, "xmax", 1.5, "Highest x to show")
+    fs.Float64Var(&o.win.YLo, "ymin", -0.45, "Lowest y to show")
+    fs.Float64Var(&o.win.YHi, "ymax", 0.45, "Highest y to show")
+    fs.IntVar(&o.width, "width", 1600, "Width of the image in pixels")
+    fs.IntVar(&o.height, "height", 1000, "Height of the image in pixels")
+    fs.Float64Var(&o.gamma, "gamma", 2, "Gamma applied after log tone mapping")
+    fs.StringVar(&o.out, "plot", out, "Output file for the image (png)")
+}
+
+func (o *henon_opts) check() error {
+    if o.trans < 0 || o.n <= 0 {
+        return invalidf("need trans >= 0 and n > 0")
+    }
+    return o.export_opts.check()
+}
+
+// check the window and raster too
+func (o *henon_opts) check_raster() error {
+    switch {
+    case o.win.XLo >= o.win.XHi || o.win.YLo >= o.win.YHi:
+        return invalidf("need xmin < xmax and ymin < ymax")
+    case o.width <= 0 || o.height <= 0 || o.gamma <= 0:
+        return invalidf("need width, height and gamma > 0")
+    }
+    return o.check()
+}
+
+func (o *henon_opts) henon() maps.Henon {
+    return maps.Henon{A: o.a, B: o.b}
+}
+
+/////////////////////////////////////////////////////
+// Purpose: The henon group: the attractor, its    //
+// Liapunov spectrum and dimension, the diagram in //
+// a and zooms into its layers                     //
+// Return: The group                               //
+/////////////////////////////////////////////////////
+func henon_group() group {
+    return group{name: "henon", summary: "the Henon map (x, y) -> (1 - a x^2 + y, b x)", commands: []command{
+        {name: "attractor", summary: "Density image of the attractor (png), with its Liapunov exponents and\nKaplan-Yorke dimension", setup: func(fs *flag.FlagSet) func() error {
+            o := &henon_opts{}
+            o.flags(fs, 2000000)
+            o.raster_flags(fs, "henon.png")
+            return func() error { return henon_attractor(o) }
+        }},
+        {name: "spectrum", summary: "Both Liapunov exponents by QR accumulation of the Jacobian, and the\nKaplan-Yorke dimension, as the averaging length grows", setup: func(fs *flag.FlagSet) func() error {
+            o := &henon_opts{}
+            o.flags(fs, 10000000)
+            return func() error { return henon_spectrum(o) }
+        }},
+        {name: "diagram", summary: "Bifurcation diagram of x against a at fixed b (png)", setup: func(fs *flag.FlagSet) func() error {
+            o := &henon_opts{}
+            o.flags(fs, 20000)
+            o.raster_flags(fs, "henon_diagram.png")
+            a_min := fs.Float64("amin", 0, "Lowest a to show")
+            a_max := fs.Float64("amax", 1.4, "Highest a to show")
+            return func() error {
+                if *a_min >= *a_max {
+                    return invalidf("need amin < amax")
+                }
+                return henon_diagram(o, *a_min, *a_max)
+            }
+        }},
+        {name: "zoom", summary: "Nested windows around the fixed point showing the layered (Cantor set\nacross) structure of the attractor (png per window)", setup: func(fs *flag.FlagSet) func() error {
+            o := &henon_opts{}
+            o.flags(fs, 500000)
+            o.raster_flags(fs, "henon_zoom.png")
+            n_zoom := fs.Int("nzoom", 3, "Number of nested windows after the first")
+            factor := fs.Float64("factor", 8, "Magnification from one window to the next")
+            max_iter := fs.Float64("maxiter", 5e9, "Most iterations to spend filling a window with n points")
+            return func() error {
+                if *n_zoom < 0 || *factor <= 1 || *max_iter < float64(o.n) {
+                    return invalidf("need nzoom >= 0, factor > 1 and maxiter >= n")
+                }
+                return henon_zoom(o, *n_zoom, *factor, int64(*max_iter))
+            }
+        }},
+    }}
+}
+
+// print the Liapunov spectrum of o's map over n steps
+func henon_exponents(o *henon_opts, n int) ([2]float64, error) {
+    l, err := analysis.Spectrum(o.henon(), o.x0, o.y0, o.trans, n)
+    if err != nil {
+        return l, err
+    }
+    fmt.Printf("lambda_1 = %.6f, lambda_2 = %.6f (sum %.6f, ln|b| = %.6f), Kaplan-Yorke dimension = %.4f\n",
+        l[0], l[1], l[0]+l[1], math.Log(math.Abs(o.b)), analysis.KaplanYorke(l))
+    return l, nil
+}
+
+// add the hit pixels of a raster of win to table at their centres,
+// each row starting with the values lead
+func pixel_rows(table *export.Table, counts []float64, win analysis.Rect, width, height int, lead ...float64) {
+    if table == nil {
+        return
+    }
+    for i, c := range counts {
+        if c > 0 {
+            col, row := i%width, i/width
+            table.Add(append(lead, win.XLo+(float64(col)+0.5)*(win.XHi-win.XLo)/float64(width), win.YHi-(float64(row)+0.5)*(win.YHi-win.YLo)/float64(height), c)...)
+        }
+    }
+}
+
+func henon_attractor(o *henon_opts) error {
+    if err := o.check_raster(); err != nil {
+        return err
+    }
+    counts, _, err := analysis.PlaneDensity(o.henon(), o.x0, o.y0, o.win, o.width, o.height, o.trans, int64(o.n), int64(o.n))
+    if err != nil {
+        return err
+    }
+    if err := plotting.SavePNG(plotting.ToneMap(counts, o.width, o.height, o.gamma), o.out); err != nil {
+        return err
+    }
+    if _, err := henon_exponents(o, o.n); err != nil {
+        return err
+    }
+    table := o.table("x", "y", "count")
+    pixel_rows(table, counts, o.win, o.width, o.height)
+    return o.save(table)
+}
+
+func henon_spectrum(o *henon_opts) error {
+    if err := o.check(); err != nil {
+        return err
+    }
+    table := o.table("n", "lambda1", "lambda2", "dimension")
+    fmt.Printf("%12s %12s %12s %12s %12s\n", "n", "lambda_1", "lambda_2", "sum", "D_KY")
+    for n := 100; ; n *= 10 {
+        if n > o.n {
+            n = o.n
+        }
+        l, err := analysis.Spectrum(o.henon(), o.x0, o.y0, o.trans, n)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("%12d %12.6f %12.6f %12.6f %12.6f\n", n, l[0], l[1], l[0]+l[1], analysis.KaplanYorke(l))
+        table.Add(float64(n), l[0], l[1], analysis.KaplanYorke(l))
+        if n == o.n {
+            break
+        }
+    }
+    fmt.Printf("lambda_1 + lambda_2 should be ln|b| = %.6f\n", math.Log(math.Abs(o.b)))
+    return o.save(table)
+}
+
+func henon_diagram(o *henon_opts, a_min, a_max float64) error {
+    if err := o.check_raster(); err != nil {
+        return err
+    }
+    family := func(a float64) maps.Map2D { return maps.Henon{A: a, B: o.b} }
+    counts := analysis.ParamDensity(family, o.x0, o.y0, a_min, a_max, o.win.XLo, o.win.XHi, o.width, o.height, o.trans, o.n)
+    if err := plotting.SavePNG(plotting.ToneMap(counts, o.width, o.height, o.gamma), o.out); err != nil {
+        return err
+    }
+
+    table := o.table("a", "x", "count")
+    for i, c := range counts {
+        if c > 0 {
+            col, row := i%o.width, i/o.width
+            table.Add(a_min+(float64(col)+0.5)*(a_max-a_min)/float64(o.width), o.win.XHi-(float64(row)+0.5)*(o.win.XHi-o.win.XLo)/float64(o.height), c)
+        }
+    }
+    return o.save(table)
+}
+
+////////////////////////////////////////////////////////
+// Purpose: Render the attractor in the given window  //
+// and then in windows factor, factor^2, ... times    //
+// smaller around the fixed point, each filled with n //
+// points, where more and more of the layers that     //
+// looked like single curves split apart              //
+// Return: Nothing (one png per window saved)         //
+////////////////////////////////////////////////////////
+func henon_zoom(o *henon_opts, n_zoom int, factor float64, max_iter int64) error {
+    if err := o.check_raster(); err != nil {
+        return err
+    }
+    h := o.henon()
+    fx, fy, ok := h.FixedPoint()
+    if !ok {
+        return fmt.Errorf("the map has no fixed point to zoom in on for a = %v, b = %v", o.a, o.b)
+    }
+    fmt.Printf("zooming in on the fixed point (%.10f, %.10f)\n", fx, fy)
+
+    fmt.Printf("%3s %14s %14s %14s %14s %12s %14s\n", "k", "x_min", "x_max", "y_min", "y_max", "points", "iterations")
+    table := o.table("k", "x", "y", "count")
+    for k := 0; k <= n_zoom; k++ {
+        win := o.win
+        if k > 0 {
+            win = o.win.Zoom(fx, fy, math.Pow(factor, float64(k)))
+        }
+        counts, iters, err := analysis.PlaneDensity(h, o.x0, o.y0, win, o.width, o.height, o.trans, int64(o.n), max_iter)
+        if err != nil {
+            return err
+        }
+        if err := plotting.SavePNG(plotting.ToneMap(counts, o.width, o.height, o.gamma), plotting.Numbered(o.out, k)); err != nil {
+            return err
+        }
+
+        hits := 0.
+        for _, c := range counts {
+            hits += c
+        }
+        fmt.Printf("%3d %14.10f %14.10f %14.10f %14.10f %12.0f %14d\n", k, win.XLo, win.XHi, win.YLo, win.YHi, hits, iters)
+        pixel_rows(table, counts, win, o.width, o.height, float64(k))
+    }
+    return o.save(table)
+}
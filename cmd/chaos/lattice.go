@@ -0,0 +1,175 @@
+package main
+
+import (
+    "fmt"
+    "flag"
+    "strings"
+    "math/rand"
+    "github.com/tmitchel/chaos/maps"
+    "github.com/tmitchel/chaos/analysis"
+    "github.com/tmitchel/chaos/plotting"
+)
+
+/////////////////////////////////////////////////////
+// Purpose: Options of the lattice subcommands:    //
+// the map at each site, how the sites couple and  //
+// the random state they start from                //
+/////////////////////////////////////////////////////
+type lattice_opts struct {
+    export_opts
+    map_name, coupling, boundary string
+    r, eps, edge, tol float64
+    sites, trans, n, pmax int
+    seed int64
+    out string
+}
+
+func (o *lattice_opts) flags(fs *flag.FlagSet, n int, out string) {
+    fs.StringVar(&o.map_name, "map", "logistic", "Map at each site ("+strings.Join(maps.Names(), ", ")+")")
+    fs.Float64Var(&o.r, "r", 3.9, "Value of r at every site")
+    fs.StringVar(&o.coupling, "coupling", "diffusive", "Coupling between sites (diffusive or global)")
+    fs.StringVar(&o.boundary, "boundary", "periodic", "Ends of a diffusive lattice (periodic or fixed)")
+    fs.Float64Var(&o.edge, "edge", 0, "Value held beyond each end with -boundary fixed")
+    fs.IntVar(&o.sites, "sites", 200, "Number of sites")
+    fs.IntVar(&o.trans, "trans", 1000, "Number of transient steps to discard")
+    fs.IntVar(&o.n, "n", n, "Number of steps to keep")
+    fs.IntVar(&o.pmax, "pmax", 64, "Longest period in time or space to look for")
+    fs.Float64Var(&o.tol, "tol", 1e-6, "Difference below which two sites or steps count as equal")
+    fs.Int64Var(&o.seed, "seed", 1, "Seed for the random starting state")
+    fs.StringVar(&o.out, "plot", out, "Output file for the plot")
+    o.export_opts.flags(fs)
+}
+
+// check the options and build the lattice (Eps is left to the caller)
+func (o *lattice_opts) lattice() (analysis.Lattice, error) {
+    var l analysis.Lattice
+    m, err := maps.Lookup(o.map_name)
+    if err != nil {
+        return l, invalidf("%v", err)
+    }
+    r_lo, r_hi := m.RRange()
+    switch {
+    case o.r < r_lo || o.r > r_hi:
+        return l, invalidf("r must be in the range [%v, %v]", r_lo, r_hi)
+    case o.sites < 2 || o.trans < 0 || o.n <= 0 || o.pmax <= 0 || o.tol <= 0:
+        return l, invalidf("need sites >= 2, trans >= 0 and n, pmax and tol > 0")
+    }
+    l = analysis.Lattice{Map: m, R: o.r, Edge: o.edge}
+    switch o.coupling {
+    case "diffusive":
+        l.Coupling = analysis.Diffusive
+    case "global":
+        l.Coupling = analysis.Global
+    default:
+        return l, invalidf("coupling must be diffusive or global")
+    }
+    switch o.boundary {
+    case "periodic":
+        l.Boundary = analysis.Periodic
+    case "fixed":
+        l.Boundary = analysis.Fixed
+    default:
+        return l, invalidf("boundary must be periodic or fixed")
+    }
+    return l, o.export_opts.check()
+}
+
+// the random starting state
+func (o *lattice_opts) start(m maps.Map1D) []float64 {
+    return analysis.RandomState(m, o.sites, rand.New(rand.NewSource(o.seed)))
+}
+
+/////////////////////////////////////////////////////
+// Purpose: The lattice group: the space-time      //
+// pattern for one coupling and synchronization as //
+// the coupling is swept                           //
+// Return: The group                               //
+/////////////////////////////////////////////////////
+func lattice_group() group {
+    return group{name: "lattice", summary: "coupled map lattices of any map in the logistic group", commands: []command{
+        {name: "run", summary: "Space-time raster of the lattice (png) and its pattern statistics", setup: func(fs *flag.FlagSet) func() error {
+            o := &lattice_opts{}
+            eps := fs.Float64("eps", 0.3, "Coupling strength (0 to 1)")
+            scale := fs.Int("scale", 3, "Pixels per site and step")
+            every := fs.Int("every", 1, "Draw every k-th step (2 hides period-2 flicker, showing domains)")
+            o.flags(fs, 300, "lattice.png")
+            return func() error {
+                if *eps < 0 || *eps > 1 || *scale <= 0 || *every <= 0 {
+                    return invalidf("need 0 <= eps <= 1 and scale and every > 0")
+                }
+                return lattice_run(o, *eps, *scale, *every)
+            }
+        }},
+        {name: "sweep", summary: "Synchronization error and pattern class against the coupling strength", setup: func(fs *flag.FlagSet) func() error {
+            o := &lattice_opts{}
+            eps_min := fs.Float64("epsmin", 0, "Lowest coupling")
+            eps_max := fs.Float64("epsmax", 1, "Highest coupling")
+            d_eps := fs.Float64("deps", 0.01, "Step in the coupling")
+            o.flags(fs, 500, "lattice_sync.pdf")
+            return func() error {
+                if *eps_min < 0 || *eps_max > 1 || *eps_min >= *eps_max || *d_eps <= 0 {
+                    return invalidf("need 0 <= epsmin < epsmax <= 1 and deps > 0")
+                }
+                return lattice_sweep(o, *eps_min, *eps_max, *d_eps)
+            }
+        }},
+    }}
+}
+
+// print one line of pattern statistics
+func print_stats(s analysis.PatternStats) {
+    fmt.Printf("sync error %10.3e (last step %10.3e); period %3d; wavelength %3d; clusters %4d; %s\n",
+        s.SyncErr, s.FinalErr, s.Period, s.Wavelength, s.Clusters, s.Class)
+}
+
+func lattice_run(o *lattice_opts, eps float64, scale, every int) error {
+    l, err := o.lattice()
+    if err != nil {
+        return err
+    }
+    l.Eps = eps
+    frames := l.Run(o.start(l.Map), o.trans, o.n)
+
+    var shown [][]float64
+    for t := 0; t < len(frames); t += every {
+        shown = append(shown, frames[t])
+    }
+    x_lo, x_hi := l.Map.XRange()
+    if err := plotting.SavePNG(plotting.SpaceTime(shown, x_lo, x_hi, scale), o.out); err != nil {
+        return err
+    }
+    print_stats(analysis.Classify(frames, o.pmax, o.tol))
+
+    table := o.table("step", "site", "x")
+    for t, row := range frames {
+        for i, x := range row {
+            table.Add(float64(o.trans+t+1), float64(i), x)
+        }
+    }
+    return o.save(table)
+}
+
+func lattice_sweep(o *lattice_opts, eps_min, eps_max, d_eps float64) error {
+    l, err := o.lattice()
+    if err != nil {
+        return err
+    }
+    var epss []float64
+    for i := 0; eps_min+float64(i)*d_eps <= eps_max+1e-12; i++ {
+        epss = append(epss, eps_min+float64(i)*d_eps)
+    }
+    stats := analysis.SyncSweep(l, o.start(l.Map), epss, o.trans, o.n, o.pmax, o.tol)
+
+    errs := make([]float64, len(epss))
+    table := o.table("eps", "sync_err", "final_err", "period", "wavelength", "clusters")
+    for i, s := range stats {
+        fmt.Printf("eps = %5.3f; ", epss[i])
+        print_stats(s)
+        errs[i] = s.SyncErr
+        table.Add(epss[i], s.SyncErr, s.FinalErr, float64(s.Period), float64(s.Wavelength), float64(s.Clusters))
+    }
+    if err := o.save(table); err != nil {
+        return err
+    }
+    return plotting.SyncSweep(epss, errs, o.out)
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+    "log"
+    "flag"
+    "time"
+    "runtime"
+    "strings"
+    "github.com/tmitchel/chaos/maps"
+    "github.com/tmitchel/chaos/analysis"
+)
+
+/////////////////////////////////////////////////////
+// Purpose: Every option of the logistic (1D map)  //
+// subcommands. Each subcommand only registers the //
+// flags it uses, the rest keep their defaults     //
+/////////////////////////////////////////////////////
+type logistic_opts struct {
+    export_opts
+    map_name string
+    r, x0 float64
+    n, trans, avg, pmax int
+    r_min, r_max, x_min, x_max, d_r float64
+    out string
+    width, height, keep int
+    gamma float64
+    n_max, n_zoom, k, fade int
+    tol, eps, p_tol float64
+    prec uint
+    color_periods bool
+    noise, s_min, s_max float64
+    n_sig int
+    noise_kind string
+    seed int64
+    eps_lo, eps_hi float64
+    n_eps int
+}
+
+// the defaults, which are for the logistic map (main moves the
+// range ones to match the chosen map)
+func default_opts() *logistic_opts {
+    return &logistic_opts{map_name: "logistic", r: 2, x0: 0.5,
+        n: 300, trans: 2000, avg: 1000, pmax: 64,
+        r_min: 0, r_max: 4, x_min: 0, x_max: 1, d_r: 0.001,
+        width: 2000, height: 1200, keep: 20000, gamma: 2,
+        n_max: 12, n_zoom: 5, k: 1, fade: 20,
+        tol: 1e-6, eps: 1e-10, p_tol: 1e-6, prec: 128,
+        noise_kind: "additive", seed: 1}
+}
+
+// a set of related flags
+type opt_group func(fs *flag.FlagSet, o *logistic_opts)
+
+// the map alone, for subcommands that find their own r and x
+// windows
+func map_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.StringVar(&o.map_name, "map", o.map_name, "Map to iterate ("+strings.Join(maps.Names(), ", ")+")")
+}
+
+// the map and the (r, x) window it's studied on
+func window_flags(fs *flag.FlagSet, o *logistic_opts) {
+    map_flags(fs, o)
+    fs.Float64Var(&o.r_min, "rmin", o.r_min, "Lowest r to compute (defaults to the bottom of the map's r range)")
+    fs.Float64Var(&o.r_max, "rmax", o.r_max, "Highest r to compute (defaults to the top of the map's r range)")
+    fs.Float64Var(&o.x_min, "xmin", o.x_min, "Lowest x to show/start from (defaults to the bottom of the map's x range)")
+    fs.Float64Var(&o.x_max, "xmax", o.x_max, "Highest x to show/start from (defaults to the top of the map's x range)")
+}
+
+// the single (r, x0) a subcommand looks at
+func point_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.Float64Var(&o.r, "r", o.r, "Value of r (defaults to the middle of the r window)")
+    fs.Float64Var(&o.x0, "x0", o.x0, "Starting value x0 (defaults to the map's critical point)")
+}
+
+// the starting value alone, for subcommands sweeping r
+func start_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.Float64Var(&o.x0, "x0", o.x0, "Starting value x0 (defaults to the map's critical point)")
+}
+
+func iter_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.IntVar(&o.n, "n", o.n, "Number of iterations to complete")
+}
+
+func trans_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.IntVar(&o.trans, "trans", o.trans, "Number of transient iterations to discard")
+}
+
+func period_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.IntVar(&o.pmax, "pmax", o.pmax, "Longest period to look for")
+}
+
+func step_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.Float64Var(&o.d_r, "dr", o.d_r, "Step in r (defaults to the r window over 4000)")
+}
+
+func avg_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.IntVar(&o.avg, "avg", o.avg, "Number of iterations to average the Liapunov exponent over")
+}
+
+func raster_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.IntVar(&o.width, "width", o.width, "Width of the raster in pixels")
+    fs.IntVar(&o.height, "height", o.height, "Height of the raster in pixels")
+    fs.IntVar(&o.keep, "keep", o.keep, "Number of iterates per pixel column of the raster")
+    fs.Float64Var(&o.gamma, "gamma", o.gamma, "Gamma applied after log tone mapping of the raster")
+}
+
+// noise added to the map every step
+func noise_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.Float64Var(&o.noise, "noise", o.noise, "Amplitude of Gaussian noise added every step (0 for none)")
+    noise_src_flags(fs, o)
+}
+
+// the kind of noise and its random stream
+func noise_src_flags(fs *flag.FlagSet, o *logistic_opts) {
+    fs.StringVar(&o.noise_kind, "kind", o.noise_kind, "Noise added to x (additive) or to r (parametric)")
+    fs.Int64Var(&o.seed, "seed", o.seed, "Seed for the noise")
+}
+
+// where the plot and the exported data go (out is the plot's
+// default name, or "" for subcommands that only print)
+func output_flags(out string) opt_group {
+    return func(fs *flag.FlagSet, o *logistic_opts) {
+        if out != "" {
+            fs.StringVar(&o.out, "plot", out, "Output file for the plot")
+        }
+        o.export_opts.flags(fs)
+    }
+}
+
+/////////////////////////////////////////////////////
+// Purpose: The logistic group, one subcommand per //
+// analysis mode                                   //
+// Return: The group                               //
+/////////////////////////////////////////////////////
+func logistic_group() group {
+    return group{name: "logistic", summary: "one-dimensional maps (-map picks the logistic map or another family)", commands: []command{
+        logistic_cmd("diagram", "Scatter plot of the Feigenbaum diagram, x_n for every (r, x0) on the grid",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.BoolVar(&o.color_periods, "colorp", false, "Colour the diagram by attractor period")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                var periods []int
+                if o.color_periods {
+                    periods = d.periods(o.trans, o.x0, o.pmax)
+                }
+                return d.do_plotting(o.n, periods, o.out)
+            }, window_flags, start_flags, iter_flags, trans_flags, period_flags, noise_flags, output_flags("feigenbaum.pdf")),
+
+        logistic_cmd("raster", "Density raster of the Feigenbaum diagram (png)", nil,
+            func(d data_holder, o *logistic_opts) error {
+                return d.do_raster(o.x0, o.r_min, o.r_max, o.x_min, o.x_max, o.width, o.height, o.trans, o.keep, o.gamma, o.out)
+            }, window_flags, start_flags, trans_flags, raster_flags, noise_flags, output_flags("feigenbaum.png")),
+
+        logistic_cmd("zoom", "Nested rasters around the superstable points R_n showing self-similarity",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.IntVar(&o.n_zoom, "nzoom", o.n_zoom, "Number of nested windows to render")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                return d.do_zoom(o.n_zoom, o.width, o.height, o.trans, o.keep, o.gamma, o.out)
+            }, map_flags, trans_flags, raster_flags, output_flags("zoom.png")),
+
+        logistic_cmd("lyapunov", "Liapunov exponent against r", nil,
+            func(d data_holder, o *logistic_opts) error {
+                return d.plot_liapunov(o.x0, o.r_min, o.r_max, o.d_r, o.trans, o.avg, o.out)
+            }, window_flags, start_flags, step_flags, trans_flags, avg_flags, noise_flags, output_flags("liapunov.pdf")),
+
+        logistic_cmd("entropy", "Kneading sequence and topological entropy against r, plotted with the\nLiapunov exponent (-n sets the length of the kneading sequences)", nil,
+            func(d data_holder, o *logistic_opts) error {
+                return d.entropy_print(o.n, o.x0, o.r_min, o.r_max, o.d_r, o.trans, o.avg, o.out)
+            }, window_flags, start_flags, iter_flags, step_flags, trans_flags, avg_flags, output_flags("entropy.pdf")),
+
+        logistic_cmd("mss", "Superstable orbits in order of r with their kneading words (the MSS sequence)",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.IntVar(&o.pmax, "pmax", 8, "Longest period to list (at most 10)")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                if o.pmax > 10 {
+                    return invalidf("need pmax <= 10")
+                }
+                return d.mss_print(o.r_min, o.r_max, o.pmax)
+            }, window_flags, output_flags("")),
+
+        logistic_cmd("bifurcations", "Bifurcation points r_n with their uncertainties", nil,
+            func(d data_holder, o *logistic_opts) error {
+                return d.bifurcation(o.trans, o.x0, o.pmax)
+            }, window_flags, start_flags, trans_flags, period_flags, output_flags("")),
+
+        logistic_cmd("periods", "Attractor period for each r on the grid", nil,
+            func(d data_holder, o *logistic_opts) error {
+                return d.period_print(o.trans, o.x0, o.pmax)
+            }, window_flags, start_flags, trans_flags, period_flags, noise_flags, output_flags("")),
+
+        logistic_cmd("noise", "Onset of chaos and longest period seen against noise amplitude, and the\nscaling of the shift of r_inf (-rmin/-rmax bracket the onset)",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.Float64Var(&o.s_min, "smin", 1e-7, "Smallest noise amplitude")
+                fs.Float64Var(&o.s_max, "smax", 1e-3, "Largest noise amplitude")
+                fs.IntVar(&o.n_sig, "nsig", 9, "Number of amplitudes, spaced evenly in log")
+                fs.IntVar(&o.avg, "avg", 100000, "Number of iterations to average the Liapunov exponent over")
+                noise_src_flags(fs, o)
+            },
+            func(d data_holder, o *logistic_opts) error {
+                if o.s_min <= 0 || o.s_max <= o.s_min || o.n_sig < 2 {
+                    return invalidf("need 0 < smin < smax and nsig >= 2")
+                }
+                return d.noise_scaling(o.x0, o.r_min, o.r_max, o.x_min, o.x_max, o.s_min, o.s_max, o.n_sig,
+                    o.noise_kind == "parametric", o.seed, o.trans, o.avg, o.pmax, o.out)
+            }, window_flags, start_flags, trans_flags, period_flags, output_flags("noise.pdf")),
+
+        logistic_cmd("constants", "Feigenbaum delta and alpha from the bifurcation points, with extrapolation", nil,
+            func(d data_holder, o *logistic_opts) error {
+                return d.feigenbaum(o.trans, o.x0, o.pmax)
+            }, window_flags, start_flags, trans_flags, period_flags, output_flags("")),
+
+        logistic_cmd("superstable", "Superstable parameters R_n by Newton's method, with delta and alpha",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.IntVar(&o.n_max, "nmax", o.n_max, "Highest n to solve for R_n (period 2^n)")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                return d.superstable_print(o.n_max)
+            }, map_flags, output_flags("")),
+
+        logistic_cmd("orbit", "Orbit of (r, x0): convergence to the attracting cycle, or the separation\nof nearby orbits (plotted to -plot) when r is chaotic",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.Float64Var(&o.eps, "eps", o.eps, "Perturbation of x0 used to measure sensitive dependence")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                if analysis.Regular(d.Map, o.r, o.x0, o.trans, o.pmax) {
+                    return d.conv_print(o.n, o.trans, o.pmax, o.r, o.x0)
+                }
+                return d.chaos_print(o.n, o.eps, o.trans, o.avg, o.out, o.r, o.x0)
+            }, window_flags, point_flags, iter_flags, trans_flags, period_flags, avg_flags, output_flags("separation.pdf")),
+
+        logistic_cmd("cobweb", "Cobweb diagram of f^k for (r, x0)",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.IntVar(&o.k, "k", o.k, "Draw the cobweb of f^k")
+                fs.IntVar(&o.fade, "fade", o.fade, "Number of transient steps to draw faded")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                return d.do_cobweb(o.n, o.r, o.x0, o.k, o.fade, o.out)
+            }, window_flags, point_flags, iter_flags, output_flags("cobweb.pdf")),
+
+        logistic_cmd("rate", "Fitted convergence rate against the cycle multiplier, at r and across the window", nil,
+            func(d data_holder, o *logistic_opts) error {
+                return d.do_rate(o.x0, o.r, o.r_min, o.r_max, o.d_r, o.trans, o.pmax, o.out)
+            }, window_flags, point_flags, step_flags, trans_flags, period_flags, output_flags("rate.pdf")),
+
+        logistic_cmd("transient", "Heatmap of the transient length over the (r, x0) grid",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.Float64Var(&o.tol, "tol", o.tol, "Distance from the attractor that ends the transient")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                return d.do_transients(o.x0, o.trans, o.pmax, o.tol, o.out)
+            }, window_flags, start_flags, trans_flags, period_flags, output_flags("transient.pdf")),
+
+        logistic_cmd("basins", "Coexisting attractors across every x0 at each r, with their basin fractions,\nand the diagram coloured by attractor",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.IntVar(&o.keep, "keep", 1000, "Number of iterates after the transient used to tell the attractors apart")
+                fs.Float64Var(&o.tol, "tol", 1e-4, "Distance within which the points of two cycles are the same")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                if o.keep < 2*o.pmax || o.tol <= 0 {
+                    return invalidf("need keep >= 2*pmax and tol > 0")
+                }
+                return d.basins_print(o.trans, o.keep, o.pmax, o.tol, o.out)
+            }, window_flags, trans_flags, period_flags, output_flags("basins.pdf")),
+
+        logistic_cmd("dimension", "Box-counting, information and correlation dimensions of the attractor at r\n(r_inf by default for the logistic map), with log-log fits",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.IntVar(&o.n, "n", 1000000, "Number of iterates to sample the attractor with")
+                fs.IntVar(&o.trans, "trans", 100000, "Number of transient iterations to discard")
+                fs.Float64Var(&o.eps_hi, "epsmax", 1e-2, "Largest scale")
+                fs.Float64Var(&o.eps_lo, "epsmin", 1e-6, "Smallest scale")
+                fs.IntVar(&o.n_eps, "neps", 25, "Number of scales, spaced evenly in log")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                if o.n < 2 || o.eps_lo <= 0 || o.eps_hi <= o.eps_lo || o.n_eps < 3 {
+                    return invalidf("need n >= 2, 0 < epsmin < epsmax and neps >= 3")
+                }
+                at_inf := o.map_name == "logistic" && o.r == maps.LogisticRInf
+                return d.dimension_print(o.r, o.x0, o.trans, o.n, o.eps_hi, o.eps_lo, o.n_eps, at_inf, o.out)
+            }, window_flags, point_flags, output_flags("dimension.pdf")),
+
+        logistic_cmd("precision", "Orbit of (r, x0) in float64 against big.Float, and where they part",
+            func(fs *flag.FlagSet, o *logistic_opts) {
+                fs.UintVar(&o.prec, "prec", o.prec, "Mantissa bits for the high-precision run")
+                fs.Float64Var(&o.p_tol, "ptol", o.p_tol, "Difference at which the float64 and high-precision runs count as different")
+            },
+            func(d data_holder, o *logistic_opts) error {
+                return d.prec_print(o.n, o.r, o.x0, o.prec, o.p_tol)
+            }, window_flags, point_flags, iter_flags, output_flags("")),
+    }}
+}
+
+// a logistic subcommand: extra registers any flags of its own
+// (may be nil) and mode runs it on the grid
+func logistic_cmd(name, summary string, extra opt_group, mode func(d data_holder, o *logistic_opts) error, flags ...opt_group) command {
+    setup := func(fs *flag.FlagSet) func() error {
+        o := default_opts()
+        for _, f := range flags {
+            f(fs, o)
+        }
+        if extra != nil {
+            extra(fs, o)
+        }
+        return func() error {
+            return run_logistic(fs, o, name, mode)
+        }
+    }
+    return command{name: name, summary: summary, setup: setup}
+}
+
+//////////////////////////////////////////////////////////
+// Purpose: Fill in the map-dependent defaults, check   //
+// the options and run one logistic subcommand          //
+// Return: Any usage or run error                       //
+//////////////////////////////////////////////////////////
+func run_logistic(fs *flag.FlagSet, o *logistic_opts, name string, mode func(d data_holder, o *logistic_opts) error) error {
+    start := time.Now()
+
+    m, err := maps.Lookup(o.map_name)
+    if err != nil {
+        return invalidf("%v", err)
+    }
+    r_lo, r_hi := m.RRange()
+    x_lo, x_hi := m.XRange()
+
+    // the defaults are for the logistic map, so move them to match
+    // the chosen map when they weren't given explicitly
+    set := make(map[string]bool)
+    fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+    if !set["rmin"] {
+        o.r_min = r_lo
+    }
+    if !set["rmax"] {
+        o.r_max = r_hi
+        if name == "rate" && o.map_name == "logistic" {
+            // nothing converges past the onset of chaos
+            o.r_max = maps.LogisticRInf
+        }
+    }
+    if name == "noise" && o.map_name == "logistic" {
+        // bracket the onset of chaos, below the period-6 window
+        if !set["rmin"] {
+            o.r_min = 3.3
+        }
+        if !set["rmax"] {
+            o.r_max = 3.6
+        }
+    }
+    if !set["xmin"] {
+        o.x_min = x_lo
+    }
+    if !set["xmax"] {
+        o.x_max = x_hi
+    }
+    if !set["dr"] {
+        o.d_r = (o.r_max - o.r_min) / analysis.GridR
+    }
+    if !set["r"] {
+        o.r = (o.r_min + o.r_max) / 2
+        if name == "dimension" && o.map_name == "logistic" {
+            // the Cantor set at the onset of chaos
+            o.r = maps.LogisticRInf
+        }
+    }
+    if !set["x0"] {
+        o.x0 = m.Crit(o.r)
+        if o.x0 < o.x_min || o.x0 > o.x_max {
+            o.x0 = (o.x_min + o.x_max) / 2
+        }
+    }
+
+    if err := o.check(r_lo, r_hi, x_lo, x_hi); err != nil {
+        return err
+    }
+
+    if o.noise > 0 {
+        m = maps.NewNoisy(m, o.noise, o.noise_kind == "parametric", o.seed)
+    }
+    grid, err := analysis.NewGrid(m, o.r_min, o.r_max, o.x_min, o.x_max)
+    if err != nil {
+        return invalidf("%v", err)
+    }
+    results := data_holder{Grid: grid, r: o.r, x0: o.x0, noise: o.noise, export_opts: o.export_opts}
+    if err := mode(results, o); err != nil {
+        return err
+    }
+
+    // print some timing info
+    elapsed := time.Since(start)
+    calc_time := results.Stats.Calc
+    log.Printf("Time spent on calculation: %s", calc_time)
+    log.Printf("Time spent on other: %s", elapsed - calc_time)
+    log.Printf("Processing completed in: %s", elapsed)
+
+    // and how much work/memory that took
+    var mem runtime.MemStats
+    runtime.ReadMemStats(&mem)
+    log.Printf("Grid cells computed: %d (%d iterations) on %d workers", results.Stats.Cells, results.Stats.Iters, runtime.GOMAXPROCS(0))
+    log.Printf("Memory allocated: %.1f MB (%.1f MB obtained from the OS)", float64(mem.TotalAlloc)/(1<<20), float64(mem.Sys)/(1<<20))
+    return nil
+}
+
+// check the options against the map's ranges
+func (o *logistic_opts) check(r_lo, r_hi, x_lo, x_hi float64) error {
+    switch {
+    case o.r_min < r_lo || o.r_max > r_hi || o.r_min >= o.r_max || o.d_r <= 0:
+        return invalidf("need %v <= rmin < rmax <= %v and dr > 0", r_lo, r_hi)
+    case o.x_min < x_lo || o.x_max > x_hi || o.x_min >= o.x_max:
+        return invalidf("need %v <= xmin < xmax <= %v", x_lo, x_hi)
+    case o.r > o.r_max || o.r < o.r_min:
+        return invalidf("r must be in the range [%v, %v]", o.r_min, o.r_max)
+    case o.x0 > o.x_max || o.x0 < o.x_min:
+        return invalidf("x0 must be in the range [%v, %v]", o.x_min, o.x_max)
+    case o.n <= 0 || o.trans < 0 || o.avg <= 0 || o.pmax <= 0:
+        return invalidf("need n > 0, trans >= 0, avg > 0 and pmax > 0")
+    case o.width <= 0 || o.height <= 0 || o.keep <= 0 || o.gamma <= 0:
+        return invalidf("need width, height, keep and gamma > 0")
+    case o.n_max < 2 || o.n_zoom < 1:
+        return invalidf("need nmax >= 2 and nzoom >= 1")
+    case o.k <= 0 || o.fade < 0:
+        return invalidf("need k > 0 and fade >= 0")
+    case o.tol <= 0:
+        return invalidf("need tol > 0")
+    case o.eps < 1e-15 || o.eps >= o.x_max-o.x_min:
+        return invalidf("need 1e-15 <= eps < xmax - xmin")
+    case o.p_tol <= 0 || o.prec < 53:
+        return invalidf("need ptol > 0 and prec >= 53")
+    case o.noise < 0 || (o.noise_kind != "additive" && o.noise_kind != "parametric"):
+        return invalidf("need noise >= 0 and kind additive or parametric")
+    }
+    return o.export_opts.check()
+}
@@ -0,0 +1,191 @@
+package main
+
+///////////////////////////////////////////////////////
+// Purpose: Single front-end for every analysis, as  //
+// subcommands grouped by system:                    //
+//   chaos logistic diagram|lyapunov|...  [flags]    //
+//   chaos quadratic mandelbrot|julia|strip [flags]  //
+//   chaos henon attractor|spectrum|...   [flags]    //
+//   chaos standard portrait|sea|critical [flags]    //
+//   chaos lattice run|sweep              [flags]    //
+//   chaos rossler run|section            [flags]    //
+//   chaos duffing run|compare            [flags]    //
+// Return: Exit code 0 on success, 1 when a run      //
+// fails and 2 for invalid usage (with the message   //
+// on stderr)                                        //
+///////////////////////////////////////////////////////
+
+import (
+    "os"
+    "io"
+    "fmt"
+    "flag"
+    "errors"
+    "strings"
+)
+
+// exit codes
+const (
+    exit_fail  = 1
+    exit_usage = 2
+)
+
+// a subcommand: setup registers its flags on fs and returns the
+// function to call once they've been parsed
+type command struct {
+    name, summary string
+    setup func(fs *flag.FlagSet) func() error
+}
+
+// a system and its subcommands
+type group struct {
+    name, summary string
+    commands []command
+}
+
+// every group, in the order they're listed in the help
+func groups() []group {
+    return []group{logistic_group(), quadratic_group(), henon_group(), standard_group(), lattice_group(), rossler_group(), duffing_group()}
+}
+
+// an error in how chaos was invoked rather than in the run itself
+type usage_error struct {
+    cmd, msg string
+}
+
+func (u usage_error) Error() string {
+    return u.msg
+}
+
+// usage error for the subcommand cmd
+func usagef(cmd, format string, args ...interface{}) error {
+    return usage_error{cmd: cmd, msg: fmt.Sprintf(format, args...)}
+}
+
+// usage error for whichever subcommand is running (an invalid
+// flag value)
+func invalidf(format string, args ...interface{}) error {
+    return usagef("", format, args...)
+}
+
+func main() {
+    err := run(os.Args[1:])
+    if err == nil {
+        return
+    }
+    var u usage_error
+    if errors.As(err, &u) {
+        fmt.Fprintf(os.Stderr, "%s: %v\n", u.cmd, u.msg)
+        fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", u.cmd)
+        os.Exit(exit_usage)
+    }
+    fmt.Fprintln(os.Stderr, "chaos:", err)
+    os.Exit(exit_fail)
+}
+
+////////////////////////////////////////////////////////
+// Purpose: Pick the group and subcommand from args,  //
+// parse its flags and run it                         //
+// Return: Any error from parsing or the run          //
+////////////////////////////////////////////////////////
+func run(args []string) error {
+    if len(args) == 0 {
+        return usagef("chaos", "missing system")
+    }
+    if is_help(args[0]) {
+        print_usage(os.Stdout)
+        return nil
+    }
+
+    var g *group
+    all := groups()
+    for i := range all {
+        if all[i].name == args[0] {
+            g = &all[i]
+        }
+    }
+    if g == nil {
+        return usagef("chaos", "unknown system %q", args[0])
+    }
+    path := "chaos " + g.name
+    if len(args) == 1 {
+        return usagef(path, "missing subcommand")
+    }
+    if is_help(args[1]) {
+        print_group(os.Stdout, *g)
+        return nil
+    }
+
+    var cmd *command
+    for i := range g.commands {
+        if g.commands[i].name == args[1] {
+            cmd = &g.commands[i]
+        }
+    }
+    if cmd == nil {
+        return usagef(path, "unknown subcommand %q", args[1])
+    }
+    path += " " + cmd.name
+
+    fs := flag.NewFlagSet(path, flag.ContinueOnError)
+    exec := cmd.setup(fs)
+    fs.Usage = func() {
+        fmt.Fprintf(fs.Output(), "Usage: %s [flags]\n\n%s\n\nFlags:\n", path, cmd.summary)
+        fs.PrintDefaults()
+    }
+
+    // keep flag's own messages quiet so every usage error is
+    // reported the same way
+    fs.SetOutput(io.Discard)
+    if err := fs.Parse(args[2:]); err != nil {
+        if err == flag.ErrHelp {
+            fs.SetOutput(os.Stdout)
+            fs.Usage()
+            return nil
+        }
+        return usagef(path, "%v", err)
+    }
+    if fs.NArg() > 0 {
+        return usagef(path, "unexpected argument %q", fs.Arg(0))
+    }
+    fs.SetOutput(os.Stderr)
+
+    // validation errors from exec come back tagged with the
+    // subcommand they belong to
+    err := exec()
+    var u usage_error
+    if errors.As(err, &u) && u.cmd == "" {
+        u.cmd = path
+        return u
+    }
+    return err
+}
+
+// whether arg asks for help
+func is_help(arg string) bool {
+    return arg == "help" || arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+// list every group and subcommand
+func print_usage(w io.Writer) {
+    fmt.Fprintln(w, "Usage: chaos <system> <subcommand> [flags]")
+    for _, g := range groups() {
+        fmt.Fprintln(w)
+        print_commands(w, g)
+    }
+    fmt.Fprintln(w, "\nRun 'chaos <system> <subcommand> --help' for the flags of a subcommand.")
+}
+
+// list the subcommands of one group
+func print_group(w io.Writer, g group) {
+    fmt.Fprintf(w, "Usage: chaos %s <subcommand> [flags]\n\n", g.name)
+    print_commands(w, g)
+    fmt.Fprintf(w, "\nRun 'chaos %s <subcommand> --help' for the flags of a subcommand.\n", g.name)
+}
+
+func print_commands(w io.Writer, g group) {
+    fmt.Fprintf(w, "%s: %s\n", g.name, g.summary)
+    for _, c := range g.commands {
+        fmt.Fprintf(w, "  %-13s %s\n", c.name, strings.Replace(c.summary, "\n", " ", -1))
+    }
+}
@@ -0,0 +1,787 @@
+package main
+
+import (
+    "fmt"
+    "math"
+    "image"
+    "github.com/tmitchel/chaos/maps"
+    "github.com/tmitchel/chaos/analysis"
+    "github.com/tmitchel/chaos/plotting"
+)
+
+/////////////////////////////////////////////////////
+// Purpose: The (r, x0) grid along with the point  //
+// to print and where computed data gets exported  //
+// (nowhere when out is empty). Each mode prints   //
+// and plots the results of the analysis package   //
+// and returns any error for main to report        //
+/////////////////////////////////////////////////////
+type data_holder struct {
+    *analysis.Grid
+    export_opts
+    r, x0, noise float64
+}
+
+/////////////////////////////////////////////////////////
+// Purpose: Find the bifurcation points                //
+// Returns: Print to command line every point that     //
+// could be resolved, with its uncertainty             //
+/////////////////////////////////////////////////////////
+func (d data_holder) bifurcation(trans int, x0 float64, pmax int) error {
+    var points []analysis.BifPoint
+    d.Timed(func() {
+        points = d.BifurcationPoints(d.Periods(trans, x0, pmax), x0)
+    })
+    if len(points) == 0 {
+        fmt.Println("No bifurcation points found")
+    }
+    table := d.table("from", "to", "r", "lo", "hi")
+    for _, pt := range points {
+        fmt.Printf("period %4d -> %4d at r = %.10f +/- %.1e  [%.10f, %.10f]\n", pt.From, pt.To, pt.R, (pt.Hi-pt.Lo)/2, pt.Lo, pt.Hi)
+        table.Add(float64(pt.From), float64(pt.To), pt.R, pt.Lo, pt.Hi)
+    }
+    return d.save(table)
+}
+
+// the attractor period for every r on the grid, telling the bands
+// of a noisy orbit apart when the map has noise
+func (d data_holder) periods(trans int, x0 float64, pmax int) []int {
+    if d.noise > 0 {
+        return d.NoisyPeriods(trans, x0, pmax)
+    }
+    return d.Periods(trans, x0, pmax)
+}
+
+/////////////////////////////////////////////////////
+// Purpose: Print the attractor period at each r   //
+// Return: Nothing (Printing to console)           //
+/////////////////////////////////////////////////////
+func (d data_holder) period_print(trans int, x0 float64, pmax int) error {
+    table := d.table("r", "period")
+    for i, p := range d.periods(trans, x0, pmax) {
+        fmt.Printf("r = %8.5f; period = %v\n", d.R(i), p)
+        table.Add(d.R(i), float64(p))
+    }
+    return d.save(table)
+}
+
+///////////////////////////////////////////////////////////
+// Purpose: Estimate the Feigenbaum constants from the   //
+// bifurcation points, with Aitken and Richardson        //
+// extrapolation of the successive ratios                //
+// Return: Nothing (convergence tables printed to the    //
+// console)                                              //
+///////////////////////////////////////////////////////////
+func (d data_holder) feigenbaum(trans int, x0 float64, pmax int) error {
+    var est analysis.Estimates
+    var err error
+    d.Timed(func() {
+        est, err = d.Feigenbaum(trans, x0, pmax)
+    })
+    if err != nil {
+        return err
+    }
+    chain := est.Chain
+
+    fmt.Println("Period-doubling points")
+    fmt.Printf("%3s %6s %18s %10s %18s\n", "n", "period", "r_n", "+/-", "r_inf (Richardson)")
+    for n, pt := range chain {
+        fmt.Printf("%3d %6d %18.14f %10.1e", n+1, pt.To, pt.R, (pt.Hi-pt.Lo)/2)
+        if n > 0 {
+            fmt.Printf(" %18.14f\n", pt.R+(pt.R-chain[n-1].R)/(analysis.Delta-1))
+        } else {
+            fmt.Printf(" %18s\n", "-")
+        }
+    }
+
+    print_estimates("delta", est.Deltas, analysis.Delta)
+    print_estimates("alpha", est.Alphas, analysis.Alpha)
+
+    // delta_n and alpha_n are listed against the last point they use
+    table := d.table("n", "period", "r", "err", "delta", "alpha")
+    for n, pt := range chain {
+        delta, alpha := math.NaN(), math.NaN()
+        if n >= 2 && n-2 < len(est.Deltas) {
+            delta = est.Deltas[n-2]
+        }
+        if n >= 2 && n-2 < len(est.Alphas) {
+            alpha = est.Alphas[n-2]
+        }
+        table.Add(float64(n+1), float64(pt.To), pt.R, (pt.Hi-pt.Lo)/2, delta, alpha)
+    }
+    return d.save(table)
+}
+
+// print one convergence table of ratio estimates against the
+// known value
+func print_estimates(name string, est []float64, known float64) {
+    fmt.Printf("\n%s (known value %.12f)\n", name, known)
+    fmt.Printf("%3s %16s %10s %16s %10s %16s %10s\n", "n", name+"_n", "error", "Aitken", "error", "Richardson", "error")
+    for n, v := range est {
+        fmt.Printf("%3d %16.12f %10.2e", n+1, v, math.Abs(v-known))
+        if n >= 1 {
+            rich := analysis.Richardson(est, n, 1/analysis.Delta)
+            if n >= 2 {
+                ait := analysis.Aitken(est, n)
+                fmt.Printf(" %16.12f %10.2e", ait, math.Abs(ait-known))
+            } else {
+                fmt.Printf(" %16s %10s", "-", "-")
+            }
+            fmt.Printf(" %16.12f %10.2e", rich, math.Abs(rich-known))
+        }
+        fmt.Println()
+    }
+}
+
+///////////////////////////////////////////////////////////////
+// Purpose: Print the superstable parameters R_n along with  //
+// the delta and alpha estimates they give                   //
+// Return: Nothing (table printed to the console)            //
+///////////////////////////////////////////////////////////////
+func (d data_holder) superstable_print(n_max int) error {
+    var rs, errs []float64
+    var err error
+    d.Timed(func() {
+        rs, errs, err = analysis.Superstable(d.Map, n_max)
+    })
+    if err != nil {
+        return err
+    }
+    ds := analysis.Widths(d.Map, rs)
+
+    table := d.table("n", "period", "R", "step", "delta", "alpha")
+    fmt.Printf("%3s %6s %20s %9s %16s %10s %16s %10s\n", "n", "period", "R_n", "step", "delta_n", "error", "alpha_n", "error")
+    for n, r := range rs {
+        fmt.Printf("%3d %6d %20.16f %9.1e", n, 1<<uint(n), r, errs[n])
+        delta, alpha := math.NaN(), math.NaN()
+        if n >= 2 {
+            delta = (rs[n-1] - rs[n-2]) / (r - rs[n-1])
+            alpha = -ds[n-1] / ds[n]
+            fmt.Printf(" %16.12f %10.2e %16.12f %10.2e", delta, math.Abs(delta-analysis.Delta), alpha, math.Abs(alpha-analysis.Alpha))
+        }
+        fmt.Println()
+        table.Add(float64(n), float64(int(1)<<uint(n)), r, errs[n], delta, alpha)
+    }
+    return d.save(table)
+}
+
+////////////////////////////////////////////////////
+// Purpose: Plot the Liapunov exponent vs r value //
+// for r in [r_init, r_fin] with step dr          //
+// Returns: A saved pdf of the plot               //
+////////////////////////////////////////////////////
+func (d data_holder) plot_liapunov(x0, r_init, r_fin, dr float64, trans, n_avg int, out string) error {
+    rs, expos := d.LiapunovCurve(x0, r_init, r_fin, dr, trans, n_avg)
+    table := d.table("r", "lambda")
+    for i := range rs {
+        table.Add(rs[i], expos[i])
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    return plotting.Liapunov(rs, expos, r_init, r_fin, out)
+}
+
+//////////////////////////////////////////////////////////
+// Purpose: Print the kneading sequence and topological //
+// entropy for r in [r_init, r_fin] with step dr, and   //
+// plot the entropy next to the Liapunov exponent       //
+// Return: A saved pdf of the plot                      //
+//////////////////////////////////////////////////////////
+func (d data_holder) entropy_print(n int, x0, r_init, r_fin, dr float64, trans, n_avg int, out string) error {
+    var rs, hs, expos []float64
+    var words []string
+    d.Timed(func() {
+        rs, hs, words = d.EntropyCurve(r_init, r_fin, dr, n)
+        _, expos = d.LiapunovCurve(x0, r_init, r_fin, dr, trans, n_avg)
+    })
+
+    table := d.table("r", "h_top", "lambda")
+    for i, r := range rs {
+        word := words[i]
+        if len(word) > 32 {
+            word = word[:32] + "..."
+        }
+        fmt.Printf("r = %8.5f; h_top = %.5f; lambda = %8.5f; K = %s\n", r, hs[i], expos[i], word)
+        table.Add(r, hs[i], expos[i])
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    return plotting.Entropy(rs, hs, expos, r_init, r_fin, out)
+}
+
+////////////////////////////////////////////////////////
+// Purpose: List the superstable orbits of period up  //
+// to pmax in [r_init, r_fin] in order of r, the MSS  //
+// sequence of periodic windows                       //
+// Return: Nothing (table printed to the console)     //
+////////////////////////////////////////////////////////
+func (d data_holder) mss_print(r_init, r_fin float64, pmax int) error {
+    var orbits []analysis.MSSOrbit
+    d.Timed(func() {
+        orbits = analysis.MSS(d.Map, r_init, r_fin, pmax)
+    })
+    counts := make([]int, pmax+1)
+
+    table := d.table("period", "R", "h_top")
+    fmt.Printf("%6s %20s %8s  %s\n", "period", "R", "h_top", "kneading word")
+    for _, orb := range orbits {
+        word := orb.Word
+        if word == "" {
+            word = "-"
+        }
+        h := analysis.Entropy(orb.Word + "C")
+        fmt.Printf("%6d %20.15f %8.5f  %s\n", orb.Period, orb.R, h, word)
+        table.Add(float64(orb.Period), orb.R, h)
+        counts[orb.Period]++
+    }
+
+    fmt.Println("\nWindows found per period")
+    for p := 1; p <= pmax; p++ {
+        fmt.Printf("%6d %6d\n", p, counts[p])
+    }
+    return d.save(table)
+}
+
+/////////////////////////////////////////////////////////////////
+// Purpose: Handle producing the pdf of the Feigenbaum Diagram //
+// (coloured by attractor period when periods is given, with   //
+// one entry per r as returned by Periods())                   //
+// Return: Nothing (pdf saved to system)                       //
+/////////////////////////////////////////////////////////////////
+func (d data_holder) do_plotting(n int, periods []int, out string) error {
+    // compute Xn for every cell
+    xn := make([]float64, d.NR*d.NX)
+    d.Sweep(len(xn), func(i int) {
+        xn[i] = d.Iterate(i/d.NX, i%d.NX, n)
+    })
+
+    rs := make([]float64, len(xn))
+    var cell_periods []int
+    if periods != nil {
+        cell_periods = make([]int, len(xn))
+    }
+    table := d.table("r", "x0", "x", "period")
+    for i, x := range xn {
+        indr := i / d.NX
+        period := 0
+        if periods != nil {
+            period = periods[indr]
+            cell_periods[i] = period
+        }
+        rs[i] = d.R(indr)
+        table.Add(d.R(indr), d.X(i%d.NX), x, float64(period))
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    return plotting.Diagram(rs, xn, cell_periods, d.R(0), d.R(d.NR), d.X(0), d.X(d.NX), out)
+}
+
+////////////////////////////////////////////////////////////////
+// Purpose: Produce the density raster of the Feigenbaum      //
+// Diagram for r in [r_lo, r_hi] and x in [x_lo, x_hi]        //
+// Return: Nothing (png saved to system)                      //
+////////////////////////////////////////////////////////////////
+func (d data_holder) do_raster(x0, r_lo, r_hi, x_lo, x_hi float64, width, height, trans, keep int, gamma float64, out string) error {
+    var counts []float64
+    d.Timed(func() {
+        counts = analysis.Density(d.Map, x0, r_lo, r_hi, x_lo, x_hi, width, height, trans, keep)
+    })
+    if err := plotting.SavePNG(plotting.ToneMap(counts, width, height, gamma), out); err != nil {
+        return err
+    }
+
+    // export the pixels that were hit, at their centres
+    table := d.table("r", "x", "count")
+    for i, c := range counts {
+        if c > 0 {
+            col, row := i%width, i/width
+            table.Add(r_lo+(float64(col)+0.5)*(r_hi-r_lo)/float64(width), x_hi-(float64(row)+0.5)*(x_hi-x_lo)/float64(height), c)
+        }
+    }
+    return d.save(table)
+}
+
+////////////////////////////////////////////////////////////////
+// Purpose: Render nested windows around the superstable      //
+// points R_n to show the self-similarity of the cascade      //
+// (see analysis.ZoomWindows). Windows where d_n < 0 are      //
+// flipped so every frame has the same orientation            //
+// Return: Nothing (one png per window saved to system)       //
+////////////////////////////////////////////////////////////////
+func (d data_holder) do_zoom(n_zoom, width, height, trans, keep int, gamma float64, out string) error {
+    var rs []float64
+    var err error
+    d.Timed(func() {
+        rs, _, err = analysis.Superstable(d.Map, n_zoom)
+    })
+    if err != nil {
+        return err
+    }
+
+    fmt.Printf("%3s %18s %18s %18s %18s %10s %10s\n", "n", "r_min", "r_max", "x_min", "x_max", "r scale", "x scale")
+    table := d.table("n", "R", "r_min", "r_max", "x_min", "x_max")
+    wins := analysis.ZoomWindows(d.Map, rs)
+    for i, w := range wins {
+        var img *image.RGBA
+        d.Timed(func() {
+            counts := analysis.Density(d.Map, w.C, w.RLo, w.RHi, w.XLo, w.XHi, width, height, trans<<uint(w.N), keep)
+            img = plotting.ToneMap(counts, width, height, gamma)
+        })
+        if w.D < 0 {
+            plotting.FlipVertical(img)
+        }
+        if err := plotting.SavePNG(img, plotting.Numbered(out, w.N)); err != nil {
+            return err
+        }
+
+        fmt.Printf("%3d %18.14f %18.14f %18.14f %18.14f", w.N, w.RLo, w.RHi, w.XLo, w.XHi)
+        if i > 0 {
+            prev := wins[i-1]
+            fmt.Printf(" %10.6f %10.6f", (prev.RHi-prev.RLo)/(w.RHi-w.RLo), -prev.D/w.D)
+        }
+        fmt.Println()
+        table.Add(float64(w.N), w.R, w.RLo, w.RHi, w.XLo, w.XHi)
+    }
+    return d.save(table)
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Draw the cobweb diagram of f^k for a single     //
+// (r, x0) over the x window                                //
+// Return: Nothing (pdf saved to system)                    //
+//////////////////////////////////////////////////////////////
+func (d data_holder) do_cobweb(n int, r, x0 float64, k, fade int, out string) error {
+    var xs []float64
+    d.Timed(func() {
+        xs = analysis.PowerOrbit(d.Map, r, x0, k, n)
+    })
+    table := d.table("n", "x")
+    for i, x := range xs {
+        table.Add(float64(i), x)
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    return plotting.Cobweb(d.Map, r, xs, k, fade, d.X(0), d.X(d.NX), out)
+}
+
+///////////////////////////////////////////////////////////////
+// Purpose: Heatmap of how long every (r, x0) cell takes to  //
+// get within tol of the attractor found from x0. Transients //
+// diverge at each r_n and r with no attracting cycle        //
+// (chaos) are left grey                                     //
+// Return: Nothing (pdf saved to system)                     //
+///////////////////////////////////////////////////////////////
+func (d data_holder) do_transients(x0 float64, trans, pmax int, tol float64, out string) error {
+    z := d.Transients(x0, trans, pmax, tol)
+    table := d.table("r", "x0", "iterations")
+    for i, v := range z {
+        table.Add(d.R(i/d.NX), d.X(i%d.NX), v)
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    return plotting.Transients(z, d.NR, d.NX, d.RMin, d.R(d.NR), d.XMin, d.X(d.NX), trans, tol, out)
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Find the attractors coexisting at each r across //
+// every x0 on the grid (see analysis.Multistability) and   //
+// plot the diagram coloured by attractor                   //
+// Return: Nothing (prints the ranges of r with more than   //
+// one attractor, their periods and mean basin fractions)   //
+//////////////////////////////////////////////////////////////
+func (d data_holder) basins_print(trans, keep, pmax int, tol float64, out string) error {
+    bm := d.Multistability(trans, keep, pmax, tol)
+
+    table := d.table("r", "attractor", "period", "lo", "hi", "fraction")
+    for ir, basins := range bm.Basins {
+        for k, b := range basins {
+            table.Add(d.R(ir), float64(k+1), float64(b.Period), b.Lo, b.Hi, b.Fraction)
+        }
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    // neighbouring r with the same attractor periods make one range
+    same := func(a, b []analysis.Basin) bool {
+        if len(a) != len(b) {
+            return false
+        }
+        for k := range a {
+            if a[k].Period != b[k].Period {
+                return false
+            }
+        }
+        return true
+    }
+    multi := 0
+    for lo := 0; lo < d.NR; {
+        hi := lo + 1
+        for hi < d.NR && same(bm.Basins[lo], bm.Basins[hi]) {
+            hi++
+        }
+        n_att := len(bm.Basins[lo])
+        if n_att > 1 {
+            multi += hi - lo
+            fmt.Printf("r = [%.5f, %.5f]: %v attractors, period (basin)", d.R(lo), d.R(hi-1), n_att)
+            for k := range bm.Basins[lo] {
+                frac := 0.
+                for ir := lo; ir < hi; ir++ {
+                    frac += bm.Basins[ir][k].Fraction
+                }
+                fmt.Printf("  %v (%.2f)", bm.Basins[lo][k].Period, frac/float64(hi-lo))
+            }
+            fmt.Println()
+        }
+        lo = hi
+    }
+    fmt.Printf("%v of %v values of r have more than one attractor (period 0: chaotic or longer than %v)\n", multi, d.NR, pmax)
+
+    rs := make([]float64, len(bm.Last))
+    for i := range rs {
+        rs[i] = d.R(i / d.NX)
+    }
+    return plotting.Basins(rs, bm.Last, bm.Labels, d.R(0), d.R(d.NR), d.X(0), d.X(d.NX), out)
+}
+
+////////////////////////////////////////////////////////////
+// Purpose: Handle the printing of values/convergence for //
+// r not such that the system is in the chaotic region,   //
+// measured against the attracting cycle found for r      //
+// Return: Nothing (Printing to console)                  //
+////////////////////////////////////////////////////////////
+func (d data_holder) conv_print(n, trans, pmax int, r_print, x0_print float64) error {
+    r, x0 := d.RIndex(r_print), d.XIndex(x0_print)
+    r_val := d.R(r)
+    var att analysis.Attractor
+    var ok bool
+    var xs []float64
+    d.Timed(func() {
+        att, ok = analysis.FindAttractor(d.Map, r_val, d.X(x0), trans, pmax)
+        xs = d.Orbit(r, x0, 0, n)
+    })
+    if !ok {
+        // no cycle to compare with so just print values
+        fmt.Printf("No attracting cycle with period <= %v found for r = %.8g\n", pmax, r_val)
+        table := d.table("n", "x")
+        for i, current := range xs {
+            fmt.Printf("Xn = %8.6f after %v iterations\n", current, i+1)
+            table.Add(float64(i+1), current)
+        }
+        return d.save(table)
+    }
+
+    fmt.Printf("r = %.8g: attracting %v-cycle with multiplier |(f^%v)'(x*)| = %.6g\n", r_val, len(att.Cycle), len(att.Cycle), math.Abs(att.Mult))
+    for i, c := range att.Cycle {
+        fmt.Printf("  x*_%v = %.10f\n", i+1, c)
+    }
+    table := d.table("n", "x", "asympt", "diff")
+    for i, current := range xs {
+        near := att.Nearest(current)
+        fmt.Printf("Asympt: %8.6f; Xn = %8.6f with diff %9.3e after %v iterations\n", near, current, math.Abs(current-near), i+1)
+        table.Add(float64(i+1), current, near, math.Abs(current-near))
+    }
+    return d.save(table)
+}
+
+/////////////////////////////////////////////////////////////////
+// Purpose: Compare how fast orbits converge with the rate the //
+// cycle multiplier predicts, (1/p) ln|(f^p)'(x*)|, first for  //
+// (r, x0) and then for every r in [r_init, r_fin] with step   //
+// dr. Both rates go to 0 at each bifurcation point (critical  //
+// slowing down)                                               //
+// Return: Nothing (printing to console and pdf saved)         //
+/////////////////////////////////////////////////////////////////
+func (d data_holder) do_rate(x0, r, r_init, r_fin, dr float64, trans, pmax int, out string) error {
+    // the single point first
+    var att analysis.Attractor
+    var ok bool
+    var fit analysis.RateFit
+    var fitted bool
+    d.Timed(func() {
+        att, ok = analysis.FindAttractor(d.Map, r, x0, trans, pmax)
+        if ok {
+            fit, fitted = analysis.DecayRate(d.Map, r, x0, att)
+        }
+    })
+    if !ok {
+        fmt.Printf("No attracting cycle with period <= %v found for r = %.8g\n", pmax, r)
+    } else {
+        p := len(att.Cycle)
+        pred := math.Log(math.Abs(att.Mult)) / float64(p)
+        fmt.Printf("r = %.8g: attracting %v-cycle with multiplier |(f^%v)'(x*)| = %.6g\n", r, p, p, math.Abs(att.Mult))
+        fmt.Printf("Predicted rate (1/%v) ln|(f^%v)'(x*)| = %.6g per iteration\n", p, p, pred)
+        if fitted {
+            fmt.Printf("Fitted rate of ln|Xn - x*|      = %.6g +/- %.2g per iteration (n = %v..%v, %v points)\n", fit.Rate, fit.Err, fit.First, fit.Last, fit.NPts)
+            fmt.Printf("Relative difference: %.3g; distance shrinks by 1/e every %.4g iterations\n", math.Abs(fit.Rate-pred)/math.Abs(pred), -1/fit.Rate)
+        } else {
+            fmt.Println("Convergence too fast (or too slow) to fit a rate")
+        }
+    }
+
+    // and then across the window
+    rs, preds, fits := d.Rates(x0, r_init, r_fin, dr, trans, pmax)
+    table := d.table("r", "predicted", "fitted")
+    found := false
+    for i := range rs {
+        table.Add(rs[i], preds[i], fits[i])
+        found = found || !math.IsNaN(preds[i])
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+    if !found {
+        fmt.Println("No attracting cycles in the window, nothing to plot")
+        return nil
+    }
+
+    return plotting.Rate(rs, preds, fits, r_init, r_fin, out)
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Handle the printing when r is such that the     //
+// system is in the chaotic region: follow x0 and x0 + eps, //
+// print delta_n = |Xn - Xn'| and ln|delta_n|, fit the      //
+// exponential growth before the separation saturates and   //
+// plot the separation curve                                //
+// Return: Nothing (printing to console and pdf saved)      //
+//////////////////////////////////////////////////////////////
+func (d data_holder) chaos_print(n int, eps float64, trans, n_avg int, out string, r, x0 float64) error {
+    s, err := d.Separate(r, x0, eps, trans, n)
+    if err != nil {
+        return err
+    }
+
+    table := d.table("n", "x", "x_perturbed", "delta")
+    for i, delta := range s.Deltas {
+        fmt.Printf("Xn = %6.4f; Xn' = %6.4f; delta(X) = %9.3e; ln(delta) = %7.3f\n", s.Xs[i], s.XPs[i], delta, math.Log(delta))
+        table.Add(float64(i), s.Xs[i], s.XPs[i], delta)
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    sat := analysis.SatFrac * s.Width
+    fmt.Printf("r = %.8g, x0 = %.8g; after %v iterations x = %.16g, x' = %.16g (eps = %.3g)\n", s.R, s.X0, trans, s.Start, s.XP, eps)
+    if s.Sat < 0 {
+        fmt.Printf("No saturation within %v iterations (separation stayed below %.3g)\n", n, sat)
+    } else {
+        fmt.Printf("Saturation after %v iterations (separation above %.3g)\n", s.Sat, sat)
+    }
+    var liap float64
+    d.Timed(func() {
+        liap = analysis.Exponent(d.Map, s.R, s.X0, trans, n_avg)
+    })
+    if s.Fitted {
+        fmt.Printf("Fitted growth of ln(delta) over n = 0..%v: lambda = %.4f +/- %.4f\n", s.FitEnd, s.Slope, s.Err)
+    } else {
+        fmt.Println("Too few iterations before saturation to fit the growth rate")
+    }
+    fmt.Printf("Liapunov exponent from <ln|f'(x)|>: lambda = %.4f\n", liap)
+    if liap > 0 {
+        fmt.Printf("Expected saturation time ln(%.3g/eps)/lambda = %.1f iterations\n", sat, math.Log(sat/eps)/liap)
+    }
+
+    // nothing to plot if the orbits never separated
+    separated := false
+    for _, delta := range s.Deltas {
+        separated = separated || delta != 0
+    }
+    if !separated {
+        return nil
+    }
+    return plotting.Separation(s, out)
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Follow (r, x0) in float64 and with big.Float at //
+// prec bits, printing both, and report the iteration where //
+// they first differ by more than tol. A second run at      //
+// 2*prec bits shows how far the prec-bit run can be        //
+// trusted in turn                                          //
+// Return: Nothing (printing to console)                    //
+//////////////////////////////////////////////////////////////
+func (d data_holder) prec_print(n int, r, x0 float64, prec uint, tol float64) error {
+    var run analysis.PrecisionRun
+    var err error
+    d.Timed(func() {
+        run, err = analysis.Precision(d.Map, r, x0, n, prec, tol)
+    })
+    if err != nil {
+        return err
+    }
+
+    table := d.table("n", "x_float64", "x_big", "diff", "diff_big")
+    for i := range run.X {
+        fmt.Printf("n = %4d: float64 %.16f; %v bits %s; diff %9.3e\n", i, run.X[i], prec, run.Text[i], run.Diff[i])
+        table.Add(float64(i), run.X[i], run.Big[i], run.Diff[i], run.DiffBig[i])
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    if run.Split < 0 {
+        fmt.Printf("float64 and %v-bit trajectories agree to %g for all %v iterations\n", prec, tol, n)
+    } else {
+        fmt.Printf("float64 and %v-bit trajectories first disagree by more than %g at iteration %v\n", prec, tol, run.Split)
+    }
+    if run.Lost < 0 {
+        fmt.Printf("%v-bit trajectory agrees with %v bits to %g for all %v iterations\n", prec, 2*prec, tol, n)
+    } else {
+        fmt.Printf("%v-bit trajectory agrees with %v bits to %g only up to iteration %v\n", prec, 2*prec, tol, run.Lost-1)
+    }
+
+    // an error of 2^-53 grows like exp(lambda n) in the chaotic region
+    var liap float64
+    d.Timed(func() {
+        liap = analysis.Exponent(d.Map, r, x0, 0, n+1)
+    })
+    if liap > 0 {
+        fmt.Printf("Expected from lambda = %.4f: float64 lasts ln(tol/2^-53)/lambda = %.1f iterations, %v bits about %.1f\n",
+            liap, math.Log(tol/math.Pow(2, -53))/liap, prec, math.Log(tol/math.Pow(2, -float64(prec)))/liap)
+    }
+    return nil
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: For noise amplitudes from s_lo to s_hi, find    //
+// the r where the noisy Liapunov exponent turns positive   //
+// and the longest period still resolved below it, then fit //
+// the shift of r_inf against the amplitude on log-log axes //
+// and compare with ln(delta)/ln(kappa)                     //
+// Return: A saved pdf of the fit                           //
+//////////////////////////////////////////////////////////////
+func (d data_holder) noise_scaling(x0, r_lo, r_hi, x_lo, x_hi, s_lo, s_hi float64, n_sig int, parametric bool, seed int64, trans, n_avg, pmax int, out string) error {
+    r_inf := maps.LogisticRInf
+    if _, ok := d.Map.(maps.Logistic); !ok {
+        var err error
+        d.Timed(func() {
+            r_inf, err = analysis.ChaosOnset(d.Map, 0, false, seed, x0, r_lo, r_hi, trans, n_avg, 1e-10)
+        })
+        if err != nil {
+            return err
+        }
+        fmt.Printf("onset of chaos without noise (from the exponent): r_inf = %.10f\n", r_inf)
+    }
+
+    var sigmas, shifts []float64
+    table := d.table("sigma", "r_c", "shift", "max_period")
+    fmt.Printf("%10s %16s %12s %10s\n", "sigma", "r_c", "r_inf - r_c", "max period")
+    for k := 0; k < n_sig; k++ {
+        sigma := s_lo * math.Pow(s_hi/s_lo, float64(k)/float64(n_sig-1))
+        var r_c float64
+        var err error
+        d.Timed(func() {
+            r_c, err = analysis.ChaosOnset(d.Map, sigma, parametric, seed, x0, r_lo, r_hi, trans, n_avg, 1e-10)
+        })
+        if err != nil {
+            return err
+        }
+
+        // the longest period the noisy orbits still show before
+        // the onset (past it the bands of chaos can look periodic),
+        // looking close enough below it to resolve the short-lived
+        // long periods
+        noisy := maps.NewNoisy(d.Map, sigma, parametric, seed)
+        grid, err := analysis.NewGrid(noisy, math.Max(r_lo, r_c-100*math.Abs(r_inf-r_c)), r_c, x_lo, x_hi)
+        if err != nil {
+            return err
+        }
+        // count its work with the rest of the run
+        grid.Stats = d.Stats
+        max_p := 0
+        for _, p := range grid.NoisyPeriods(trans, x0, pmax) {
+            if p > max_p {
+                max_p = p
+            }
+        }
+
+        longest := fmt.Sprint(max_p)
+        if max_p == 0 {
+            longest = "none"
+        }
+        fmt.Printf("%10.3e %16.12f %12.4e %10s\n", sigma, r_c, r_inf-r_c, longest)
+        table.Add(sigma, r_c, r_inf-r_c, float64(max_p))
+        if r_inf-r_c > 0 {
+            sigmas = append(sigmas, sigma)
+            shifts = append(shifts, r_inf-r_c)
+        }
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+    if len(sigmas) < 2 {
+        return fmt.Errorf("fewer than two amplitudes moved the onset below r_inf, try larger ones")
+    }
+
+    ls, lr := make([]float64, len(sigmas)), make([]float64, len(sigmas))
+    for i := range sigmas {
+        ls[i], lr[i] = math.Log(sigmas[i]), math.Log(shifts[i])
+    }
+    slope, icept, slope_err := analysis.FitLine(ls, lr)
+    fmt.Printf("\nr_inf - r_c ~ sigma^gamma with gamma = %.4f +/- %.4f (ln(delta)/ln(kappa) = %.4f)\n", slope, slope_err, analysis.NoiseGamma)
+    return plotting.NoiseShift(sigmas, shifts, slope, icept, out)
+}
+
+//////////////////////////////////////////////////////////////
+// Purpose: Estimate the box-counting, information and      //
+// correlation dimensions of the attractor at r from an     //
+// orbit of n points (see analysis.AttractorDimensions),    //
+// compared with the values for the Feigenbaum attractor    //
+// when r is r_inf                                          //
+// Return: Nothing (prints each dimension with its          //
+// confidence interval, pdf of the fits saved to system)    //
+//////////////////////////////////////////////////////////////
+func (d data_holder) dimension_print(r, x0 float64, trans, n int, eps_hi, eps_lo float64, n_eps int, at_inf bool, out string) error {
+    var dims analysis.Dimensions
+    var err error
+    d.Timed(func() {
+        dims, err = analysis.AttractorDimensions(d.Map, r, x0, trans, n, analysis.Scales(eps_hi, eps_lo, n_eps))
+    })
+    if err != nil {
+        return err
+    }
+
+    table := d.table("eps", "ln_n", "info", "neg_ln_c")
+    fmt.Printf("%12s %12s %12s %12s\n", "eps", "ln N", "I", "-ln C")
+    for k, e := range dims.Box.Eps {
+        fmt.Printf("%12.4e %12.6f %12.6f %12.6f\n", e, dims.Box.Ys[k], dims.Info.Ys[k], dims.Corr.Ys[k])
+        table.Add(e, dims.Box.Ys[k], dims.Info.Ys[k], dims.Corr.Ys[k])
+    }
+    if err := d.save(table); err != nil {
+        return err
+    }
+
+    fmt.Printf("\nDimensions at r = %.10g from %v points (%g%% confidence intervals)\n", r, dims.Points, 100*analysis.Confidence)
+    for _, row := range []struct {
+        name  string
+        fit   analysis.DimFit
+        known float64
+    }{{"box-counting  D0", dims.Box, analysis.FeigenbaumD0},
+        {"information   D1", dims.Info, analysis.FeigenbaumD1},
+        {"correlation   D2", dims.Corr, analysis.FeigenbaumD2}} {
+        fmt.Printf("%s = %.4f +/- %.4f  [%.4f, %.4f]", row.name, row.fit.Dim, row.fit.Err, row.fit.Lo, row.fit.Hi)
+        if at_inf {
+            fmt.Printf("  (r_inf: %.3f, difference %+.4f)", row.known, row.fit.Dim-row.known)
+        }
+        fmt.Println()
+    }
+
+    // boxes can only be counted while there are points to fill them
+    last := len(dims.Box.Ys) - 1
+    if boxes := math.Exp(dims.Box.Ys[last]); boxes > 0.1*float64(dims.Points) {
+        fmt.Printf("Warning: %.0f boxes visited at eps = %g by only %v points, so the counts are saturating (raise -epsmin or -n)\n", boxes, dims.Box.Eps[last], dims.Points)
+    }
+
+    return plotting.Dimensions(dims, out)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+    "fmt"
+    "flag"
+    "github.com/tmitchel/chaos/maps"
+    "github.com/tmitchel/chaos/export"
+    "github.com/tmitchel/chaos/analysis"
+    "github.com/tmitchel/chaos/plotting"
+)
+
+/////////////////////////////////////////////////////
+// Purpose: Options of the quadratic (z^2 + c)     //
+// subcommands: the complex window, raster size    //
+// and escape-time iterations                      //
+/////////////////////////////////////////////////////
+type quadratic_opts struct {
+    export_opts
+    re_min, re_max, im_min, im_max float64
+    width, height, n_iter int
+    gamma float64
+    out string
+}
+
+// register the options, with the default window and plot name
+func (o *quadratic_opts) flags(fs *flag.FlagSet, re_min, re_max, im_min, im_max float64, width, height int, out string) {
+    fs.Float64Var(&o.re_min, "remin", re_min, "Lowest real part to show")
+    fs.Float64Var(&o.re_max, "remax", re_max, "Highest real part to show")
+    fs.Float64Var(&o.im_min, "immin", im_min, "Lowest imaginary part to show")
+    fs.Float64Var(&o.im_max, "immax", im_max, "Highest imaginary part to show")
+    o.raster_flags(fs, width, height, out)
+}
+
+// the raster options alone (the strip has a window in r instead)
+func (o *quadratic_opts) raster_flags(fs *flag.FlagSet, width, height int, out string) {
+    fs.IntVar(&o.width, "width", width, "Width of the image in pixels")
+    fs.IntVar(&o.height, "height", height, "Height of the image in pixels")
+    fs.IntVar(&o.n_iter, "iter", 500, "Iterations before a point counts as inside the set")
+    fs.Float64Var(&o.gamma, "gamma", 2, "Gamma applied to the escape-time shading (larger is lighter)")
+    fs.StringVar(&o.out, "plot", out, "Output file for the image (png)")
+    o.export_opts.flags(fs)
+}
+
+func (o *quadratic_opts) check() error {
+    switch {
+    case o.re_min >= o.re_max || o.im_min >= o.im_max:
+        return invalidf("need remin < remax and immin < immax")
+    case o.width <= 0 || o.height <= 0 || o.n_iter <= 0 || o.gamma <= 0:
+        return invalidf("need width, height, iter and gamma > 0")
+    }
+    return o.export_opts.check()
+}
+
+//////////////////////////////////////////////////////
+// Purpose: The quadratic group: the Mandelbrot set //
+// and filled Julia sets of z^2 + c, the family the //
+// logistic map belongs to through c = r/2 - r^2/4  //
+// Return: The group                                //
+//////////////////////////////////////////////////////
+func quadratic_group() group {
+    return group{name: "quadratic", summary: "the quadratic family z^2 + c (c = r/2 - r^2/4 for the logistic map)", commands: []command{
+        {name: "mandelbrot", summary: "Escape-time image of the Mandelbrot set (png)", setup: func(fs *flag.FlagSet) func() error {
+            o := &quadratic_opts{}
+            o.flags(fs, -2.2, 0.6, -1.2, 1.2, 1400, 1200, "mandelbrot.png")
+            return func() error { return mandelbrot(o) }
+        }},
+        {name: "julia", summary: "Escape-time image of the filled Julia set for the logistic map at r (png)", setup: func(fs *flag.FlagSet) func() error {
+            o := &quadratic_opts{}
+            r := fs.Float64("r", 3.2, "Value of r of the logistic map, giving c = r/2 - r^2/4")
+            o.flags(fs, -2, 2, -1.5, 1.5, 1600, 1200, "julia.png")
+            return func() error { return julia(o, *r) }
+        }},
+        {name: "strip", summary: "Mandelbrot set near the real axis drawn against r, above the Feigenbaum\ndiagram over the same r window, so bulbs sit over their periodic windows (png)", setup: func(fs *flag.FlagSet) func() error {
+            o := &quadratic_opts{}
+            r_min := fs.Float64("rmin", 2.8, "Lowest r to show")
+            r_max := fs.Float64("rmax", 4, "Highest r to show")
+            fs.Float64Var(&o.im_max, "immax", 0.3, "Strip shows c + i y for |y| up to immax")
+            strip := fs.Int("sheight", 300, "Height of the Mandelbrot strip in pixels")
+            trans := fs.Int("trans", 2000, "Number of transient iterations to discard in the diagram")
+            keep := fs.Int("keep", 20000, "Number of iterates per pixel column of the diagram")
+            pmax := fs.Int("pmax", 6, "Longest period of the windows to list (at most 10)")
+            o.raster_flags(fs, 2000, 1200, "strip.png")
+            return func() error {
+                o.re_min, o.re_max, o.im_min = *r_min, *r_max, -o.im_max
+                switch {
+                case *r_min < 0 || *r_max > 4 || *r_min >= *r_max:
+                    return invalidf("need 0 <= rmin < rmax <= 4")
+                case o.im_max <= 0:
+                    return invalidf("need immax > 0")
+                case *strip <= 0 || *trans < 0 || *keep <= 0:
+                    return invalidf("need sheight > 0, trans >= 0 and keep > 0")
+                case *pmax <= 0 || *pmax > 10:
+                    return invalidf("need 0 < pmax <= 10")
+                }
+                return mandelbrot_strip(o, *strip, *trans, *keep, *pmax)
+            }
+        }},
+    }}
+}
+
+// export escape times row by row from the top of the window, with
+// the first two columns named by re and im (nil when not exporting)
+func escape_table(o *quadratic_opts, times []float64, width, height int, re, im string) *export.Table {
+    table := o.table(re, im, "escape")
+    if table == nil {
+        return nil
+    }
+    d_re := (o.re_max - o.re_min) / float64(width)
+    d_im := (o.im_max - o.im_min) / float64(height)
+    for i, t := range times {
+        col, row := i%width, i/width
+        table.Add(o.re_min+(float64(col)+0.5)*d_re, o.im_max-(float64(row)+0.5)*d_im, t)
+    }
+    return table
+}
+
+func mandelbrot(o *quadratic_opts) error {
+    if err := o.check(); err != nil {
+        return err
+    }
+    times := analysis.Mandelbrot(o.re_min, o.re_max, o.im_min, o.im_max, o.width, o.height, o.n_iter)
+    if err := plotting.SavePNG(plotting.EscapeMap(times, o.width, o.height, o.n_iter, o.gamma), o.out); err != nil {
+        return err
+    }
+    return o.save(escape_table(o, times, o.width, o.height, "re", "im"))
+}
+
+func julia(o *quadratic_opts, r float64) error {
+    if err := o.check(); err != nil {
+        return err
+    }
+    c := analysis.QuadraticC(r)
+    fmt.Printf("r = %v gives c = %v; the logistic orbits on [0, 1] lie on z in [%v, %v]\n", r, c, -r/2, r/2)
+
+    times := analysis.Julia(complex(c, 0), o.re_min, o.re_max, o.im_min, o.im_max, o.width, o.height, o.n_iter)
+    if err := plotting.SavePNG(plotting.EscapeMap(times, o.width, o.height, o.n_iter, o.gamma), o.out); err != nil {
+        return err
+    }
+    return o.save(escape_table(o, times, o.width, o.height, "re", "im"))
+}
+
+//////////////////////////////////////////////////////////
+// Purpose: Stack the Mandelbrot strip over the density //
+// raster of the logistic map for the same r columns,   //
+// and list the superstable r of each periodic window   //
+// with its c, which is the centre of a bulb            //
+// Return: Nothing (png saved to system)                //
+//////////////////////////////////////////////////////////
+func mandelbrot_strip(o *quadratic_opts, strip, trans, keep, pmax int) error {
+    if err := o.check(); err != nil {
+        return err
+    }
+    m := maps.Family["logistic"]
+    times := analysis.MandelbrotStrip(o.re_min, o.re_max, o.im_min, o.im_max, o.width, strip, o.n_iter)
+    counts := analysis.Density(m, m.Crit(o.re_min), o.re_min, o.re_max, 0, 1, o.width, o.height, trans, keep)
+    img := plotting.Stack(plotting.EscapeMap(times, o.width, strip, o.n_iter, o.gamma), plotting.ToneMap(counts, o.width, o.height, o.gamma), 2)
+    if err := plotting.SavePNG(img, o.out); err != nil {
+        return err
+    }
+
+    fmt.Printf("%6s %18s %18s %8s\n", "period", "r", "c", "in set")
+    for _, orb := range analysis.MSS(m, o.re_min, o.re_max, pmax) {
+        c := analysis.QuadraticC(orb.R)
+        fmt.Printf("%6d %18.14f %18.14f %8v\n", orb.Period, orb.R, c, analysis.EscapeTime(0, complex(c, 0), o.n_iter) < 0)
+    }
+    return o.save(escape_table(o, times, o.width, strip, "r", "im"))
+}
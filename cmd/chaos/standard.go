@@ -0,0 +1,170 @@
+package main
+
+import (
+    "fmt"
+    "flag"
+    "math"
+    "github.com/tmitchel/chaos/maps"
+    "github.com/tmitchel/chaos/analysis"
+    "github.com/tmitchel/chaos/plotting"
+)
+
+/////////////////////////////////////////////////////
+// Purpose: Options of the standard subcommands:   //
+// the grid of orbits, their length and the        //
+// exponent that marks one as chaotic              //
+/////////////////////////////////////////////////////
+type standard_opts struct {
+    export_opts
+    n_side, n int
+    lambda_c float64
+    out string
+}
+
+func (o *standard_opts) flags(fs *flag.FlagSet, n_side, n int, out string) {
+    fs.IntVar(&o.n_side, "orbits", n_side, "Orbits start on an orbits x orbits grid over the torus")
+    fs.IntVar(&o.n, "n", n, "Number of iterations of each orbit")
+    fs.Float64Var(&o.lambda_c, "lambda", 0.02, "Liapunov exponent above which an orbit counts as chaotic")
+    fs.StringVar(&o.out, "plot", out, "Output file for the plot")
+    o.export_opts.flags(fs)
+}
+
+func (o *standard_opts) check() error {
+    if o.n_side <= 0 || o.n <= 0 || o.lambda_c <= 0 {
+        return invalidf("need orbits, n and lambda > 0")
+    }
+    return o.export_opts.check()
+}
+
+/////////////////////////////////////////////////////
+// Purpose: The standard group: the phase portrait //
+// for one K, the chaotic sea across K and the K   //
+// where the last KAM torus breaks                 //
+// Return: The group                               //
+/////////////////////////////////////////////////////
+func standard_group() group {
+    return group{name: "standard", summary: "the Chirikov standard map p -> p + K sin(theta), theta -> theta + p", commands: []command{
+        {name: "portrait", summary: "Phase portrait on the torus with each regular orbit in its own colour\nand the chaotic sea in grey (png), and the chaotic fraction", setup: func(fs *flag.FlagSet) func() error {
+            o := &standard_opts{}
+            k := fs.Float64("K", 0.971635, "Kick strength K")
+            width := fs.Int("width", 1000, "Width of the image in pixels")
+            height := fs.Int("height", 1000, "Height of the image in pixels")
+            o.flags(fs, 24, 3000, "standard.png")
+            return func() error {
+                if *width <= 0 || *height <= 0 {
+                    return invalidf("need width and height > 0")
+                }
+                return standard_portrait(o, *k, *width, *height)
+            }
+        }},
+        {name: "sea", summary: "Chaotic fraction of the torus against K", setup: func(fs *flag.FlagSet) func() error {
+            o := &standard_opts{}
+            k_min := fs.Float64("kmin", 0, "Lowest K")
+            k_max := fs.Float64("kmax", 3, "Highest K")
+            d_k := fs.Float64("dk", 0.05, "Step in K")
+            o.flags(fs, 20, 2000, "standard_sea.pdf")
+            return func() error {
+                if *k_min < 0 || *k_min >= *k_max || *d_k <= 0 {
+                    return invalidf("need 0 <= kmin < kmax and dk > 0")
+                }
+                return standard_sea(o, *k_min, *k_max, *d_k)
+            }
+        }},
+        {name: "critical", summary: "K at which the last KAM torus breaks, by Greene's residue criterion on the\nsymmetric orbits with Fibonacci winding numbers approaching the golden mean", setup: func(fs *flag.FlagSet) func() error {
+            o := &standard_opts{}
+            k_min := fs.Float64("kmin", 0.5, "K below the break")
+            k_max := fs.Float64("kmax", 1.5, "K above the break")
+            q_max := fs.Int("qmax", 377, "Longest period of the approximating orbits")
+            tol := fs.Float64("tol", 1e-10, "Width in K to bisect each approximant down to")
+            o.export_opts.flags(fs)
+            return func() error {
+                if *k_min <= 0 || *k_min >= *k_max || *q_max < 8 || *tol <= 0 {
+                    return invalidf("need 0 < kmin < kmax, qmax >= 8 and tol > 0")
+                }
+                if err := o.export_opts.check(); err != nil {
+                    return err
+                }
+                return standard_critical(o, *k_min, *k_max, *q_max, *tol)
+            }
+        }},
+    }}
+}
+
+func standard_portrait(o *standard_opts, k float64, width, height int) error {
+    if err := o.check(); err != nil {
+        return err
+    }
+    orbits, err := analysis.Portrait(maps.Standard{K: k}, o.n_side, o.n, o.lambda_c)
+    if err != nil {
+        return err
+    }
+    if err := plotting.SavePNG(plotting.Portrait(orbits, width, height), o.out); err != nil {
+        return err
+    }
+
+    table := o.table("theta0", "p0", "lambda", "chaotic")
+    for _, orb := range orbits {
+        chaotic := 0.
+        if orb.Chaotic {
+            chaotic = 1
+        }
+        table.Add(orb.Theta0, orb.P0, orb.Lambda, chaotic)
+    }
+    fmt.Printf("K = %v: %v of %v orbits chaotic, chaotic sea covers %.3f of the torus\n",
+        k, int(math.Round(analysis.SeaFraction(orbits)*float64(len(orbits)))), len(orbits), analysis.SeaFraction(orbits))
+    return o.save(table)
+}
+
+func standard_sea(o *standard_opts, k_min, k_max, d_k float64) error {
+    if err := o.check(); err != nil {
+        return err
+    }
+    var ks []float64
+    for i := 0; k_min+float64(i)*d_k <= k_max+1e-12; i++ {
+        ks = append(ks, k_min+float64(i)*d_k)
+    }
+    fracs, err := analysis.SeaCurve(ks, o.n_side, o.n, o.lambda_c)
+    if err != nil {
+        return err
+    }
+
+    table := o.table("K", "fraction")
+    for i, k := range ks {
+        fmt.Printf("K = %6.3f; chaotic fraction = %.4f\n", k, fracs[i])
+        table.Add(k, fracs[i])
+    }
+    if err := o.save(table); err != nil {
+        return err
+    }
+    return plotting.SeaFraction(ks, fracs, o.out)
+}
+
+func standard_critical(o *standard_opts, k_min, k_max float64, q_max int, tol float64) error {
+    apps, err := analysis.LastTorus(k_min, k_max, q_max, tol)
+    if err != nil {
+        return err
+    }
+    table := o.table("m", "q", "K")
+    fmt.Printf("%6s %6s %14s %12s\n", "m", "q", "K (R = 1/4)", "change")
+    for i, a := range apps {
+        change := "-"
+        if i > 0 {
+            change = fmt.Sprintf("%12.2e", a.K-apps[i-1].K)
+        }
+        fmt.Printf("%6d %6d %14.10f %12s\n", a.M, a.Q, a.K, change)
+        table.Add(float64(a.M), float64(a.Q), a.K)
+    }
+
+    // the approximants alternate about the limit and close in
+    // geometrically, so the last change bounds the error
+    last := apps[len(apps)-1].K
+    unc := math.Abs(last - apps[len(apps)-2].K)
+    diff := last - maps.StandardKC
+    fmt.Printf("last KAM torus breaks at K = %.7f +/- %.1e (from q = %v)\n", last, unc, apps[len(apps)-1].Q)
+    agree := "within"
+    if math.Abs(diff) > unc {
+        agree = "outside"
+    }
+    fmt.Printf("Greene's K_c = %.7f, difference %.1e (%s the uncertainty)\n", maps.StandardKC, diff, agree)
+    return o.save(table)
+}
@@ -0,0 +1,186 @@
+////////////////////////////////////////////////////
+// Purpose: Write the numbers behind a plot or a  //
+// printout to disk as CSV, JSON lines or a NumPy //
+// .npy file, so results can be analysed without  //
+// re-running the calculation                     //
+////////////////////////////////////////////////////
+package export
+
+import (
+    "os"
+    "fmt"
+    "math"
+    "bufio"
+    "strings"
+    "strconv"
+    "encoding/json"
+    "encoding/binary"
+    "path/filepath"
+)
+
+// Formats lists the formats Write understands
+var Formats = []string{"csv", "jsonl", "npy"}
+
+////////////////////////////////////////////////////
+// Purpose: A table of named float64 columns,     //
+// filled one row at a time                       //
+// Variables: the column names and the rows (each //
+// with one value per name)                       //
+////////////////////////////////////////////////////
+type Table struct {
+    Names []string
+    Rows  [][]float64
+}
+
+// NewTable returns an empty table with the given column names
+func NewTable(names ...string) *Table {
+    return &Table{Names: names}
+}
+
+// Add appends a row, which should have one value per column.
+// Adding to a nil table does nothing, so callers can pass nil when
+// the data isn't wanted instead of building it for nothing
+func (t *Table) Add(row ...float64) {
+    if t == nil {
+        return
+    }
+    t.Rows = append(t.Rows, row)
+}
+
+// Valid reports whether format is one of Formats
+func Valid(format string) bool {
+    for _, f := range Formats {
+        if f == format {
+            return true
+        }
+    }
+    return false
+}
+
+////////////////////////////////////////////////////
+// Purpose: Write the table to out in the given   //
+// format, adding the format's extension when out //
+// doesn't already end with it                    //
+// Return: The name of the file written and any   //
+// error creating or writing it                   //
+////////////////////////////////////////////////////
+func Write(out, format string, t *Table) (string, error) {
+    if !Valid(format) {
+        return "", fmt.Errorf("export: unknown format %q (want one of %v)", format, strings.Join(Formats, ", "))
+    }
+    for i, row := range t.Rows {
+        if len(row) != len(t.Names) {
+            return "", fmt.Errorf("export: row %v has %v values for %v columns", i, len(row), len(t.Names))
+        }
+    }
+    if filepath.Ext(out) != "."+format {
+        out += "." + format
+    }
+
+    f, err := os.Create(out)
+    if err != nil {
+        return "", err
+    }
+    w := bufio.NewWriter(f)
+    switch format {
+    case "csv":
+        err = write_csv(w, t)
+    case "jsonl":
+        err = write_jsonl(w, t)
+    case "npy":
+        err = write_npy(w, t)
+    }
+    if err == nil {
+        err = w.Flush()
+    }
+    if cerr := f.Close(); err == nil {
+        err = cerr
+    }
+    return out, err
+}
+
+// a header line with the names, then one line per row
+func write_csv(w *bufio.Writer, t *Table) error {
+    if _, err := w.WriteString(strings.Join(t.Names, ",") + "\n"); err != nil {
+        return err
+    }
+    for _, row := range t.Rows {
+        vals := make([]string, len(row))
+        for i, v := range row {
+            vals[i] = strconv.FormatFloat(v, 'g', -1, 64)
+        }
+        if _, err := w.WriteString(strings.Join(vals, ",") + "\n"); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// one JSON object per row, keys in column order (NaN and Inf,
+// which JSON can't hold, become null)
+func write_jsonl(w *bufio.Writer, t *Table) error {
+    keys := make([]string, len(t.Names))
+    for i, name := range t.Names {
+        key, err := json.Marshal(name)
+        if err != nil {
+            return err
+        }
+        keys[i] = string(key)
+    }
+    for _, row := range t.Rows {
+        pairs := make([]string, len(row))
+        for i, v := range row {
+            val := "null"
+            if !math.IsNaN(v) && !math.IsInf(v, 0) {
+                val = strconv.FormatFloat(v, 'g', -1, 64)
+            }
+            pairs[i] = keys[i] + ":" + val
+        }
+        if _, err := w.WriteString("{" + strings.Join(pairs, ",") + "}\n"); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+////////////////////////////////////////////////////
+// Purpose: Write a version 1.0 .npy file holding //
+// a 1D structured array, one little-endian       //
+// float64 field per column, so np.load(f)["r"]   //
+// gives back a column by name                    //
+// Return: Any error writing                      //
+////////////////////////////////////////////////////
+func write_npy(w *bufio.Writer, t *Table) error {
+    fields := make([]string, len(t.Names))
+    for i, name := range t.Names {
+        fields[i] = "('" + name + "', '<f8')"
+    }
+    header := fmt.Sprintf("{'descr': [%v], 'fortran_order': False, 'shape': (%v,), }", strings.Join(fields, ", "), len(t.Rows))
+
+    // magic, version and header length take 10 bytes and the
+    // header is padded with spaces and a newline to a multiple of 64
+    pad := 64 - (10+len(header)+1)%64
+    if pad == 64 {
+        pad = 0
+    }
+    header += strings.Repeat(" ", pad) + "\n"
+    if len(header) > math.MaxUint16 {
+        return fmt.Errorf("export: too many columns for a .npy header")
+    }
+
+    if _, err := w.WriteString("\x93NUMPY\x01\x00"); err != nil {
+        return err
+    }
+    if err := binary.Write(w, binary.LittleEndian, uint16(len(header))); err != nil {
+        return err
+    }
+    if _, err := w.WriteString(header); err != nil {
+        return err
+    }
+    for _, row := range t.Rows {
+        if err := binary.Write(w, binary.LittleEndian, row); err != nil {
+            return err
+        }
+    }
+    return nil
+}
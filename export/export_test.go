@@ -0,0 +1,112 @@
+package export
+
+import (
+    "os"
+    "math"
+    "bytes"
+    "testing"
+    "strings"
+    "encoding/binary"
+    "path/filepath"
+)
+
+// a small table with a value JSON can't hold
+func sample() *Table {
+    t := NewTable("r", "x")
+    t.Add(3.5, 0.5)
+    t.Add(-1e-300, math.NaN())
+    t.Add(4, math.Inf(1))
+    return t
+}
+
+// write t in format to a temporary directory and read the file back
+func write_back(t *testing.T, format string, table *Table) []byte {
+    name, err := Write(filepath.Join(t.TempDir(), "data"), format, table)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if filepath.Ext(name) != "."+format {
+        t.Errorf("wrote %v, want the extension .%v", name, format)
+    }
+    b, err := os.ReadFile(name)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return b
+}
+
+func TestCSV(t *testing.T) {
+    got := string(write_back(t, "csv", sample()))
+    want := "r,x\n3.5,0.5\n-1e-300,NaN\n4,+Inf\n"
+    if got != want {
+        t.Errorf("got\n%s\nwant\n%s", got, want)
+    }
+}
+
+func TestJSONL(t *testing.T) {
+    got := string(write_back(t, "jsonl", sample()))
+    want := "{\"r\":3.5,\"x\":0.5}\n{\"r\":-1e-300,\"x\":null}\n{\"r\":4,\"x\":null}\n"
+    if got != want {
+        t.Errorf("got\n%s\nwant\n%s", got, want)
+    }
+}
+
+// the layout np.load expects: magic, version 1.0, the little-endian
+// header length, a dict header padded with spaces to a multiple of
+// 64 bytes ending in a newline, then the rows as little-endian f8
+func TestNPY(t *testing.T) {
+    table := sample()
+    b := write_back(t, "npy", table)
+    if !bytes.HasPrefix(b, []byte("\x93NUMPY\x01\x00")) {
+        t.Fatalf("bad magic or version % x", b[:8])
+    }
+    n := int(binary.LittleEndian.Uint16(b[8:10]))
+    if (10+n)%64 != 0 {
+        t.Errorf("header ends at byte %v, not a multiple of 64", 10+n)
+    }
+    header := string(b[10 : 10+n])
+    if !strings.HasSuffix(header, "\n") {
+        t.Errorf("header %q doesn't end in a newline", header)
+    }
+    dict := "{'descr': [('r', '<f8'), ('x', '<f8')], 'fortran_order': False, 'shape': (3,), }"
+    if strings.TrimRight(header, " \n") != dict {
+        t.Errorf("header %q, want %q", header, dict)
+    }
+
+    data := b[10+n:]
+    if len(data) != 8*2*len(table.Rows) {
+        t.Fatalf("%v bytes of data for %v rows of 2 columns", len(data), len(table.Rows))
+    }
+    for i, row := range table.Rows {
+        for j, want := range row {
+            got := math.Float64frombits(binary.LittleEndian.Uint64(data[8*(2*i+j):]))
+            if got != want && !(math.IsNaN(got) && math.IsNaN(want)) {
+                t.Errorf("row %v column %v: %v, want %v", i, j, got, want)
+            }
+        }
+    }
+}
+
+// the header length is a multiple of 64 for any number of columns
+func TestNPYPadding(t *testing.T) {
+    names := []string{}
+    for i := 0; i < 20; i++ {
+        names = append(names, strings.Repeat("c", i+1))
+        b := write_back(t, "npy", NewTable(names...))
+        if n := int(binary.LittleEndian.Uint16(b[8:10])); (10+n)%64 != 0 || b[10+n-1] != '\n' {
+            t.Errorf("%v columns: header length %v", len(names), n)
+        }
+    }
+}
+
+func TestWriteErrors(t *testing.T) {
+    dir := t.TempDir()
+    if _, err := Write(filepath.Join(dir, "data"), "xlsx", sample()); err == nil {
+        t.Error("no error for an unknown format")
+    }
+    table := NewTable("r", "x")
+    table.Add(1)
+    if _, err := Write(filepath.Join(dir, "data"), "csv", table); err == nil {
+        t.Error("no error for a short row")
+    }
+}
@@ -0,0 +1,128 @@
+package flows
+
+import (
+    "math/big"
+)
+
+// BigFlow is a flow that can also be stepped with big.Float at an
+// arbitrary mantissa size. Big returns a function giving the next
+// point of the trajectory through start (rounded to float64, start
+// itself first) each time it's called
+type BigFlow interface {
+    Flow
+    Big(start Point, prec uint) func() Point
+}
+
+func (r Rossler) Big(start Point, prec uint) func() Point {
+    num := func(v float64) *big.Float { return new(big.Float).SetPrec(prec).SetFloat64(v) }
+    x, y, z := num(start.X), num(start.Y), num(start.Z)
+    A, B, C, step := num(r.A), num(r.B), num(r.C), num(float64(r.StepsPerUnit))
+
+    return func() Point {
+        var pt Point
+        pt.X, _ = x.Float64()
+        pt.Y, _ = y.Float64()
+        pt.Z, _ = z.Float64()
+
+        // x, y, z = (-(y+z))/n+x, (x+a*y)/n+y, (b+z*(x-c))/n+z
+        dx := num(0).Add(y, z)
+        dx.Neg(dx)
+        dy := num(0).Mul(A, y)
+        dy.Add(x, dy)
+        dz := num(0).Sub(x, C)
+        dz.Mul(z, dz)
+        dz.Add(B, dz)
+        x = dx.Add(dx.Quo(dx, step), x)
+        y = dy.Add(dy.Quo(dy, step), y)
+        z = dz.Add(dz.Quo(dz, step), z)
+        return pt
+    }
+}
+
+// cos(t) is carried along by rotating through dt each step rather
+// than recomputed
+func (d Duffing) Big(start Point, prec uint) func() Point {
+    num := func(v float64) *big.Float { return new(big.Float).SetPrec(prec).SetFloat64(v) }
+    x, y, h, force, damp := num(start.X), num(start.Y), num(d.Dt()), num(d.F), num(0.5)
+    cos_t, sin_t := num(1), num(0)
+    cos_h, sin_h := cos_sin(h)
+
+    return func() Point {
+        var pt Point
+        pt.X, _ = x.Float64()
+        pt.Y, _ = y.Float64()
+
+        // x, y = dt*y + x, dt*(F*cos(t)-0.5*y+x-x^3) + y
+        acc := num(0).Mul(force, cos_t)
+        acc.Sub(acc, num(0).Mul(damp, y))
+        acc.Add(acc, x)
+        cube := num(0).Mul(x, x)
+        acc.Sub(acc, cube.Mul(cube, x))
+        dx := num(0).Mul(h, y)
+        x = dx.Add(dx, x)
+        y = acc.Add(acc.Mul(h, acc), y)
+
+        // t -> t + dt
+        c := num(0).Mul(cos_t, cos_h)
+        c.Sub(c, num(0).Mul(sin_t, sin_h))
+        s := num(0).Mul(sin_t, cos_h)
+        s.Add(s, num(0).Mul(cos_t, sin_h))
+        cos_t, sin_t = c, s
+        return pt
+    }
+}
+
+// cos(h) and sin(h) at h's precision from their Taylor series
+// (meant for small h, like a time step)
+func cos_sin(h *big.Float) (*big.Float, *big.Float) {
+    prec := h.Prec()
+    num := func(v int64) *big.Float { return new(big.Float).SetPrec(prec).SetInt64(v) }
+    cos, sin := num(1), new(big.Float).SetPrec(prec).Set(h)
+    term := new(big.Float).SetPrec(prec).Set(h)
+    for i := int64(2); term.Sign() != 0 && term.MantExp(nil) > -int(prec)-8; i++ {
+        // term = h^i / i! with the signs of the series
+        term.Mul(term, h)
+        term.Quo(term, num(i))
+        switch i % 4 {
+        case 0:
+            cos.Add(cos, term)
+        case 1:
+            sin.Add(sin, term)
+        case 2:
+            cos.Sub(cos, term)
+        case 3:
+            sin.Sub(sin, term)
+        }
+    }
+    return cos, sin
+}
+
+// Divergence records how long a float64 trajectory can be trusted:
+// Split is the first step where it differs from the prec-bit run
+// by more than the tolerance and Lost the first step where the
+// prec-bit run differs from a 2*prec-bit run (-1 when they never
+// do within the steps compared)
+type Divergence struct {
+    Split, Lost int
+}
+
+// Precision compares the first steps points of the trajectory
+// through start with big.Float runs at prec and 2*prec bits. The
+// comparison stops early, with an error, if the float64 run blows up
+func Precision(f BigFlow, start Point, steps int, prec uint, tol float64) (Divergence, error) {
+    pts, err := Run(f, start, steps)
+    lo := f.Big(start, prec)
+    hi := f.Big(start, 2*prec)
+
+    div := Divergence{Split: -1, Lost: -1}
+    for i := 0; i < len(pts) && (div.Split < 0 || div.Lost < 0); i++ {
+        p_lo, p_hi := lo(), hi()
+        if div.Split < 0 && max_diff(pts[i], p_lo) > tol {
+            div.Split = i
+        }
+        if div.Lost < 0 && max_diff(p_lo, p_hi) > tol {
+            div.Lost = i
+        }
+    }
+    return div, err
+}
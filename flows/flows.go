@@ -0,0 +1,106 @@
+////////////////////////////////////////////////////////
+// Purpose: Continuous-time systems (the Rossler      //
+// equations and the inverted Duffing oscillator)     //
+// stepped forward with Euler's method                //
+////////////////////////////////////////////////////////
+package flows
+
+import (
+    "fmt"
+    "math"
+)
+
+// Point is a point in the phase space of a flow (Z is unused by
+// the two-dimensional Duffing oscillator)
+type Point struct {
+    X, Y, Z float64
+}
+
+// Flow is a system stepped forward in time by a fixed Dt
+type Flow interface {
+    // Step moves p at time t forward by Dt
+    Step(p Point, t float64) Point
+    Dt() float64
+}
+
+// Rossler is the Rossler system
+// dx/dt = -(y+z), dy/dt = x + A*y, dz/dt = B + z*(x-C)
+type Rossler struct {
+    A, B, C float64
+    StepsPerUnit int
+}
+
+func (r Rossler) Step(p Point, t float64) Point {
+    n := float64(r.StepsPerUnit)
+    return Point{
+        X: (-(p.Y+p.Z))/n + p.X,
+        Y: (p.X+r.A*p.Y)/n + p.Y,
+        Z: (r.B+p.Z*(p.X-r.C))/n + p.Z,
+    }
+}
+
+func (r Rossler) Dt() float64 { return 1 / float64(r.StepsPerUnit) }
+
+// Duffing is the forced, damped inverted Duffing oscillator
+// d2x/dt2 = F*cos(t) - 0.5*dx/dt + x - x^3, with y = dx/dt
+type Duffing struct {
+    F float64
+    StepsPerUnit int
+}
+
+func (d Duffing) Step(p Point, t float64) Point {
+    dt := d.Dt()
+    return Point{
+        X: dt*p.Y + p.X,
+        Y: dt*(d.F*math.Cos(t)-0.5*p.Y+p.X-math.Pow(p.X, 3)) + p.Y,
+    }
+}
+
+func (d Duffing) Dt() float64 { return 1. / float64(d.StepsPerUnit) }
+
+// Run returns the first steps points of the trajectory through
+// start (start itself first). If the trajectory blows up the
+// points up to that step are returned along with an error
+func Run(f Flow, start Point, steps int) ([]Point, error) {
+    pts := make([]Point, 0, steps)
+    p, t := start, 0.
+    for i := 0; i < steps; i++ {
+        if bad(p) {
+            return pts, fmt.Errorf("trajectory blew up after %v steps", i)
+        }
+        pts = append(pts, p)
+        p, t = f.Step(p, t), t+f.Dt()
+    }
+    return pts, nil
+}
+
+// Section returns the points where a trajectory crosses the
+// surface plane(p) = 0 going from plane(p) < 0 to plane(p) >= 0,
+// interpolated linearly between the steps either side
+func Section(pts []Point, plane func(Point) float64) []Point {
+    cross := make([]Point, 0)
+    for i := 1; i < len(pts); i++ {
+        a, b := plane(pts[i-1]), plane(pts[i])
+        if a < 0 && b >= 0 {
+            s := a / (a - b)
+            p, q := pts[i-1], pts[i]
+            cross = append(cross, Point{X: p.X + s*(q.X-p.X), Y: p.Y + s*(q.Y-p.Y), Z: p.Z + s*(q.Z-p.Z)})
+        }
+    }
+    return cross
+}
+
+// whether any coordinate of p is NaN or infinite
+func bad(p Point) bool {
+    for _, v := range []float64{p.X, p.Y, p.Z} {
+        if math.IsNaN(v) || math.IsInf(v, 0) {
+            return true
+        }
+    }
+    return false
+}
+
+// largest difference between the coordinates of two points
+func max_diff(p, q Point) float64 {
+    return math.Max(math.Abs(p.X-q.X), math.Max(math.Abs(p.Y-q.Y), math.Abs(p.Z-q.Z)))
+}
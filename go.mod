@@ -0,0 +1,18 @@
+module github.com/tmitchel/chaos
+
+go 1.20
+
+require (
+	gonum.org/v1/gonum v0.8.1
+	gonum.org/v1/plot v0.8.1
+)
+
+require (
+	github.com/ajstarks/svgo v0.0.0-20180226025133-644b8db467af // indirect
+	github.com/fogleman/gg v1.3.0 // indirect
+	github.com/go-latex/latex v0.0.0-20200518072620-0806b477ea35 // indirect
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
+	github.com/jung-kurt/gofpdf v1.16.2 // indirect
+	golang.org/x/exp v0.0.0-20191002040644-a1355ae1e2c3 // indirect
+	golang.org/x/image v0.0.0-20200618115811-c13761719519 // indirect
+)
@@ -0,0 +1,170 @@
+package maps
+
+import (
+    "sync"
+    "math/big"
+)
+
+// BigMap1D is a map that can also be iterated with big.Float at an
+// arbitrary mantissa size. FBig returns the result at x's precision
+type BigMap1D interface {
+    FBig(r, x *big.Float) *big.Float
+}
+
+func (Logistic) FBig(r, x *big.Float) *big.Float {
+    z := big_like(x).Mul(r, x)
+    return z.Mul(z, big_like(x).Sub(big_const(x, 1), x))
+}
+
+func (Sine) FBig(r, x *big.Float) *big.Float {
+    z := big_like(x).Mul(Pi(x.Prec()), x)
+    return z.Mul(r, Sin(z))
+}
+
+func (Tent) FBig(r, x *big.Float) *big.Float {
+    if x.Cmp(big_const(x, 0.5)) < 0 {
+        return big_like(x).Mul(r, x)
+    }
+    z := big_like(x).Sub(big_const(x, 1), x)
+    return z.Mul(r, z)
+}
+
+func (Cubic) FBig(r, x *big.Float) *big.Float {
+    sq := big_like(x).Mul(x, x)
+    z := big_like(x).Mul(r, x)
+    return z.Mul(z, sq.Sub(big_const(x, 1), sq))
+}
+
+func (g Gauss) FBig(r, x *big.Float) *big.Float {
+    z := big_like(x).Mul(x, x)
+    z.Mul(z, big_const(x, -g.Alpha))
+    return z.Add(Exp(z), r)
+}
+
+func (Ricker) FBig(r, x *big.Float) *big.Float {
+    z := big_like(x).Sub(big_const(x, 1), x)
+    z.Mul(r, z)
+    return z.Mul(x, Exp(z))
+}
+
+func (Quartic) FBig(r, x *big.Float) *big.Float {
+    y := big_like(x).Mul(big_const(x, 2), x)
+    y.Sub(y, big_const(x, 1))
+    y.Mul(y, y)
+    y.Mul(y, y)
+    z := big_like(x).Sub(big_const(x, 1), y)
+    return z.Mul(r, z)
+}
+
+// a zero big.Float with the precision of x
+func big_like(x *big.Float) *big.Float {
+    return new(big.Float).SetPrec(x.Prec())
+}
+
+// v as a big.Float with the precision of x
+func big_const(x *big.Float, v float64) *big.Float {
+    return big_like(x).SetFloat64(v)
+}
+
+// whether a series term is too small to change a sum at prec bits
+func negligible(term *big.Float, prec uint) bool {
+    return term.Sign() == 0 || term.MantExp(nil) < -int(prec)
+}
+
+// pi at the highest precision asked for so far
+var pi_cache struct {
+    sync.Mutex
+    pi *big.Float
+}
+
+// Pi returns pi rounded to prec bits, from Machin's formula
+// pi = 16 atan(1/5) - 4 atan(1/239)
+func Pi(prec uint) *big.Float {
+    pi_cache.Lock()
+    defer pi_cache.Unlock()
+    if pi_cache.pi == nil || pi_cache.pi.Prec() < prec {
+        wp := prec + 32
+        pi := atan_inv(5, wp)
+        pi.Mul(pi, new(big.Float).SetPrec(wp).SetInt64(16))
+        tail := atan_inv(239, wp)
+        tail.Mul(tail, new(big.Float).SetPrec(wp).SetInt64(4))
+        pi_cache.pi = pi.Sub(pi, tail)
+    }
+    return new(big.Float).SetPrec(prec).Set(pi_cache.pi)
+}
+
+// atan(1/n) = sum_k (-1)^k / ((2k+1) n^(2k+1)) at prec bits
+func atan_inv(n int64, prec uint) *big.Float {
+    sum := new(big.Float).SetPrec(prec)
+    pow := new(big.Float).SetPrec(prec).SetInt64(n)
+    pow.Quo(new(big.Float).SetPrec(prec).SetInt64(1), pow)
+    n2 := new(big.Float).SetPrec(prec).SetInt64(n * n)
+    for k := int64(0); !negligible(pow, prec); k++ {
+        term := new(big.Float).SetPrec(prec).SetInt64(2*k + 1)
+        term.Quo(pow, term)
+        if k%2 == 0 {
+            sum.Add(sum, term)
+        } else {
+            sum.Sub(sum, term)
+        }
+        pow.Quo(pow, n2)
+    }
+    return sum
+}
+
+// Sin returns sin(x) at x's precision, from its Taylor series after
+// reducing x to [-pi, pi]
+func Sin(x *big.Float) *big.Float {
+    prec := x.Prec()
+    wp := prec + 32
+    y := new(big.Float).SetPrec(wp).Set(x)
+
+    two_pi := Pi(wp)
+    two_pi.Mul(two_pi, new(big.Float).SetPrec(wp).SetInt64(2))
+    turns := new(big.Float).SetPrec(wp).Quo(y, two_pi)
+    turns.Add(turns, new(big.Float).SetPrec(wp).SetFloat64(0.5))
+    k, _ := turns.Int(nil)
+    if turns.Sign() < 0 && !turns.IsInt() {
+        k.Sub(k, big.NewInt(1))
+    }
+    y.Sub(y, two_pi.Mul(two_pi, new(big.Float).SetPrec(wp).SetInt(k)))
+
+    // sin y = y - y^3/3! + y^5/5! - ...
+    sq := new(big.Float).SetPrec(wp).Mul(y, y)
+    term := new(big.Float).SetPrec(wp).Set(y)
+    sum := new(big.Float).SetPrec(wp).Set(y)
+    for i := int64(1); !negligible(term, wp); i++ {
+        term.Mul(term, sq)
+        term.Quo(term, new(big.Float).SetPrec(wp).SetInt64(-(2*i)*(2*i+1)))
+        sum.Add(sum, term)
+    }
+    return new(big.Float).SetPrec(prec).Set(sum)
+}
+
+// Exp returns exp(x) at x's precision, from its Taylor series after
+// halving x until it's small and squaring back up
+func Exp(x *big.Float) *big.Float {
+    prec := x.Prec()
+    halvings := 0
+    if x.Sign() != 0 {
+        halvings = x.MantExp(nil) + 8
+        if halvings < 0 {
+            halvings = 0
+        }
+    }
+    wp := prec + uint(halvings) + 32
+    y := new(big.Float).SetPrec(wp).SetMantExp(x, -halvings)
+
+    // exp y = 1 + y + y^2/2! + ...
+    term := new(big.Float).SetPrec(wp).SetInt64(1)
+    sum := new(big.Float).SetPrec(wp).SetInt64(1)
+    for i := int64(1); !negligible(term, wp); i++ {
+        term.Mul(term, y)
+        term.Quo(term, new(big.Float).SetPrec(wp).SetInt64(i))
+        sum.Add(sum, term)
+    }
+    for i := 0; i < halvings; i++ {
+        sum.Mul(sum, sum)
+    }
+    return new(big.Float).SetPrec(prec).Set(sum)
+}
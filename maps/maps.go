@@ -0,0 +1,154 @@
+////////////////////////////////////////////////////////
+// Purpose: One-dimensional maps x -> f(r, x) and the //
+// helpers to iterate them, shared by every analysis  //
+// of the Feigenbaum diagram                          //
+////////////////////////////////////////////////////////
+package maps
+
+import (
+    "fmt"
+    "math"
+    "sort"
+    "strings"
+)
+
+// LogisticRInf is the onset of chaos for the logistic map, the
+// accumulation point of its period doublings
+const LogisticRInf = 3.5699456718709449
+
+// Map1D is a one-dimensional map x -> F(r, x) controlled by a
+// single parameter r, so the analyses aren't tied to the logistic
+// map. Besides the map and its derivative in x it gives the
+// critical point (where DF = 0, or the turning point of the tent),
+// the r range the family is studied on and the interval x lives in
+type Map1D interface {
+    F(r, x float64) float64
+    DF(r, x float64) float64
+    Crit(r float64) float64
+    RRange() (float64, float64)
+    XRange() (float64, float64)
+}
+
+// Logistic is x -> r*x*(1-x)
+type Logistic struct{}
+
+func (Logistic) F(r, x float64) float64      { return r * x * (1 - x) }
+func (Logistic) DF(r, x float64) float64     { return r - 2*r*x }
+func (Logistic) Crit(r float64) float64      { return 0.5 }
+func (Logistic) RRange() (float64, float64) { return 0, 4 }
+func (Logistic) XRange() (float64, float64) { return 0, 1 }
+
+// Sine is x -> r*sin(pi*x)
+type Sine struct{}
+
+func (Sine) F(r, x float64) float64      { return r * math.Sin(math.Pi*x) }
+func (Sine) DF(r, x float64) float64     { return r * math.Pi * math.Cos(math.Pi*x) }
+func (Sine) Crit(r float64) float64      { return 0.5 }
+func (Sine) RRange() (float64, float64) { return 0, 1 }
+func (Sine) XRange() (float64, float64) { return 0, 1 }
+
+// Tent is x -> r*min(x, 1-x)
+type Tent struct{}
+
+func (Tent) F(r, x float64) float64 {
+    if x < 0.5 {
+        return r * x
+    }
+    return r * (1 - x)
+}
+func (Tent) DF(r, x float64) float64 {
+    if x < 0.5 {
+        return r
+    }
+    return -r
+}
+func (Tent) Crit(r float64) float64      { return 0.5 }
+func (Tent) RRange() (float64, float64) { return 0, 2 }
+func (Tent) XRange() (float64, float64) { return 0, 1 }
+
+// Cubic is x -> r*x*(1-x^2), symmetric so it has two critical
+// points (+-1/sqrt(3), only the positive one is reported) and
+// coexisting attractors for some r
+type Cubic struct{}
+
+func (Cubic) F(r, x float64) float64      { return r * x * (1 - x*x) }
+func (Cubic) DF(r, x float64) float64     { return r * (1 - 3*x*x) }
+func (Cubic) Crit(r float64) float64      { return 1 / math.Sqrt(3) }
+func (Cubic) RRange() (float64, float64) { return 0, 1.5 * math.Sqrt(3) }
+func (Cubic) XRange() (float64, float64) { return -1, 1 }
+
+// Gauss is x -> exp(-Alpha*x^2) + r (the "Gaussian" or mouse map)
+type Gauss struct {
+    Alpha float64
+}
+
+func (g Gauss) F(r, x float64) float64     { return math.Exp(-g.Alpha*x*x) + r }
+func (g Gauss) DF(r, x float64) float64    { return -2 * g.Alpha * x * math.Exp(-g.Alpha*x*x) }
+func (Gauss) Crit(r float64) float64      { return 0 }
+func (Gauss) RRange() (float64, float64) { return -1, 1 }
+func (Gauss) XRange() (float64, float64) { return -1, 2 }
+
+// Ricker is x -> x*exp(r*(1-x))
+type Ricker struct{}
+
+func (Ricker) F(r, x float64) float64      { return x * math.Exp(r*(1-x)) }
+func (Ricker) DF(r, x float64) float64     { return (1 - r*x) * math.Exp(r*(1-x)) }
+func (Ricker) Crit(r float64) float64      { return 1 / r }
+func (Ricker) RRange() (float64, float64) { return 0, 3.5 }
+func (Ricker) XRange() (float64, float64) { return 0, 4 }
+
+// Quartic is x -> r*(1-(2x-1)^4), flatter at the top than the
+// logistic map
+type Quartic struct{}
+
+func (Quartic) F(r, x float64) float64      { return r * (1 - math.Pow(2*x-1, 4)) }
+func (Quartic) DF(r, x float64) float64     { return -8 * r * math.Pow(2*x-1, 3) }
+func (Quartic) Crit(r float64) float64      { return 0.5 }
+func (Quartic) RRange() (float64, float64) { return 0, 1 }
+func (Quartic) XRange() (float64, float64) { return 0, 1 }
+
+// Family holds the built-in maps by name
+var Family = map[string]Map1D{
+    "logistic": Logistic{},
+    "sine":     Sine{},
+    "tent":     Tent{},
+    "cubic":    Cubic{},
+    "gauss":    Gauss{Alpha: 4.9},
+    "ricker":   Ricker{},
+    "quartic":  Quartic{},
+}
+
+// Names returns the sorted names of the maps in Family
+func Names() []string {
+    names := make([]string, 0, len(Family))
+    for name := range Family {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// Lookup returns the map called name from Family
+func Lookup(name string) (Map1D, error) {
+    m, ok := Family[name]
+    if !ok {
+        return nil, fmt.Errorf("unknown map %q (want one of %v)", name, strings.Join(Names(), ", "))
+    }
+    return m, nil
+}
+
+// Gen iterates m for a given (r, x0) and returns the keep iterates
+// after the first skip (x_skip, ..., x_skip+keep-1, with x_0 = x0)
+func Gen(m Map1D, r, x0 float64, skip, keep int) []float64 {
+    m = Start(m, r, x0)
+    x := x0
+    for i := 0; i < skip; i++ {
+        x = m.F(r, x)
+    }
+    xs := make([]float64, keep)
+    for i := range xs {
+        xs[i] = x
+        x = m.F(r, x)
+    }
+    return xs
+}
@@ -0,0 +1,94 @@
+package maps
+
+import (
+    "math"
+    "math/rand"
+    "sync"
+)
+
+// Seeded is a map that needs some state of its own for every orbit
+// (such as a stream of random numbers): Start returns the map to
+// iterate the orbit of (r, x0) with
+type Seeded interface {
+    Map1D
+    Start(r, x0 float64) Map1D
+}
+
+// Start returns the map to iterate the orbit of (r, x0) with, which
+// is m itself unless m is Seeded
+func Start(m Map1D, r, x0 float64) Map1D {
+    if s, ok := m.(Seeded); ok {
+        return s.Start(r, x0)
+    }
+    return m
+}
+
+// Noisy is a map with Gaussian noise of amplitude Sigma added every
+// step, either to x (additive, x -> f(r, x) + Sigma*xi) or to r
+// (Parametric, x -> f(r + Sigma*xi, x)), with the result held inside
+// the map's x range. DF, Crit and the ranges are the noiseless map's.
+//
+// Every orbit draws from its own math/rand stream seeded from the
+// seed, r and x0 (see Start), so a run is reproducible however the
+// orbits are spread over goroutines. Calling F on a Noisy that
+// wasn't started draws from a single stream shared under a lock,
+// which is safe but depends on the order of the calls
+type Noisy struct {
+    Map1D
+    Sigma      float64
+    Parametric bool
+
+    seed   int64
+    rng    *rand.Rand
+    shared *locked_rand
+}
+
+// a stream that can be drawn from by several goroutines
+type locked_rand struct {
+    sync.Mutex
+    rng *rand.Rand
+}
+
+// NewNoisy wraps m with noise of amplitude sigma drawn from streams
+// seeded by seed
+func NewNoisy(m Map1D, sigma float64, parametric bool, seed int64) Noisy {
+    return Noisy{Map1D: m, Sigma: sigma, Parametric: parametric, seed: seed,
+        shared: &locked_rand{rng: rand.New(rand.NewSource(seed))}}
+}
+
+// Start returns n with a stream of its own for the orbit of (r, x0)
+func (n Noisy) Start(r, x0 float64) Map1D {
+    // mix the bits of r and x0 so nearby orbits get unrelated streams
+    key := mix(math.Float64bits(r)) ^ mix(math.Float64bits(x0)+0x9E3779B97F4A7C15)
+    n.rng = rand.New(rand.NewSource(n.seed ^ int64(key)))
+    return n
+}
+
+func (n Noisy) F(r, x float64) float64 {
+    xi := n.Sigma * n.normal()
+    var y float64
+    if n.Parametric {
+        y = n.Map1D.F(r+xi, x)
+    } else {
+        y = n.Map1D.F(r, x) + xi
+    }
+    lo, hi := n.XRange()
+    return math.Max(lo, math.Min(hi, y))
+}
+
+// a standard normal draw from the orbit's stream
+func (n Noisy) normal() float64 {
+    if n.rng != nil {
+        return n.rng.NormFloat64()
+    }
+    n.shared.Lock()
+    defer n.shared.Unlock()
+    return n.shared.rng.NormFloat64()
+}
+
+// splitmix64 finaliser
+func mix(z uint64) uint64 {
+    z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+    z = (z ^ z>>27) * 0x94D049BB133111EB
+    return z ^ z>>31
+}
@@ -0,0 +1,83 @@
+package maps
+
+import "math"
+
+// Map2D is a map of the plane (x, y) -> F(x, y) with its Jacobian
+// matrix [[dx'/dx, dx'/dy], [dy'/dx, dy'/dy]]. Its parameters are
+// fields of the implementing type rather than arguments, since a 2D
+// map usually has more than one
+type Map2D interface {
+    F(x, y float64) (float64, float64)
+    Jacobian(x, y float64) [2][2]float64
+}
+
+// Henon is (x, y) -> (1 - A*x^2 + y, B*x), with the classic strange
+// attractor at A = 1.4, B = 0.3. Its Jacobian has the constant
+// determinant -B, so areas shrink by |B| every step
+type Henon struct {
+    A, B float64
+}
+
+func (h Henon) F(x, y float64) (float64, float64) {
+    return 1 - h.A*x*x + y, h.B * x
+}
+
+func (h Henon) Jacobian(x, y float64) [2][2]float64 {
+    return [2][2]float64{{-2 * h.A * x, 1}, {h.B, 0}}
+}
+
+// FixedPoint returns the fixed point with x > 0 (for A > 0), which
+// lies on the attractor, or false when the map has no fixed points
+func (h Henon) FixedPoint() (float64, float64, bool) {
+    disc := (1-h.B)*(1-h.B) + 4*h.A
+    if h.A <= 0 || disc < 0 {
+        return 0, 0, false
+    }
+    x := (-(1 - h.B) + math.Sqrt(disc)) / (2 * h.A)
+    return x, h.B * x, true
+}
+
+// Standard is the Chirikov standard map of the torus
+// p -> p + K*sin(theta), theta -> theta + p (with the new p), with
+// theta in [0, 2pi) and p in [-pi, pi). As the x and y of a Map2D
+// it takes (theta, p). It preserves area, so nothing settles onto an
+// attractor: orbits either lie on KAM tori and islands or wander a
+// chaotic sea
+type Standard struct {
+    K float64
+}
+
+func (s Standard) F(theta, p float64) (float64, float64) {
+    theta, p = s.Lift(theta, p)
+    return theta, wrap(p)
+}
+
+func (s Standard) Jacobian(theta, p float64) [2][2]float64 {
+    kc := s.K * math.Cos(theta)
+    return [2][2]float64{{1 + kc, 1}, {kc, 1}}
+}
+
+// Lift is the map on the cylinder: theta is taken mod 2pi but p is
+// left unwrapped, so an orbit can be followed across the KAM tori
+// that would otherwise bound it
+func (s Standard) Lift(theta, p float64) (float64, float64) {
+    p += s.K * math.Sin(theta)
+    theta = math.Mod(theta+p, 2*math.Pi)
+    if theta < 0 {
+        theta += 2 * math.Pi
+    }
+    return theta, p
+}
+
+// StandardKC is Greene's value for the K at which the last KAM torus
+// (the golden mean one) breaks up
+const StandardKC = 0.971635406
+
+// p wrapped into [-pi, pi)
+func wrap(p float64) float64 {
+    p = math.Mod(p+math.Pi, 2*math.Pi)
+    if p < 0 {
+        p += 2 * math.Pi
+    }
+    return p - math.Pi
+}
@@ -0,0 +1,377 @@
+package plotting
+
+import (
+    "image/color"
+    "math"
+    "strconv"
+
+    "gonum.org/v1/plot"
+    "gonum.org/v1/plot/plotter"
+    "gonum.org/v1/plot/plotutil"
+    "gonum.org/v1/plot/vg"
+    "gonum.org/v1/plot/vg/draw"
+
+    "github.com/tmitchel/chaos/analysis"
+    "github.com/tmitchel/chaos/maps"
+)
+
+// a horizontal grey line at y = 0
+func zero_line() *plotter.Function {
+    zero := plotter.NewFunction(func(float64) float64 { return 0 })
+    zero.Color = color.Gray{Y: 128}
+    return zero
+}
+
+// Liapunov plots the Liapunov exponent against r for r in
+// [r_lo, r_hi], with lambda = 0 (the edge of chaos) marked
+func Liapunov(rs, expos []float64, r_lo, r_hi float64, out string) error {
+    pts := make(plotter.XYs, len(rs))
+    for i := range rs {
+        pts[i].X, pts[i].Y = rs[i], expos[i]
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Liapunov Exponent"
+    p.X.Label.Text = "r"
+    p.Y.Label.Text = "lambda"
+    p.Add(plotter.NewGrid())
+
+    l, err := plotter.NewLine(pts)
+    if err != nil {
+        return err
+    }
+
+    p.Add(l, zero_line())
+    p.X.Min, p.X.Max = r_lo, r_hi
+    p.X.Tick.Marker = FineTicks{}
+    p.Y.Min = math.Max(p.Y.Min, analysis.LiapFloor)
+
+    return p.Save(600, 400, out)
+}
+
+// Rate plots the convergence rate predicted from the cycle
+// multiplier as a line and the fitted rate as points against r for
+// r in [r_lo, r_hi] (NaN entries are skipped, as from
+// analysis.Grid.Rates). Both go to 0 at each bifurcation point
+func Rate(rs, preds, fits []float64, r_lo, r_hi float64, out string) error {
+    var pred_pts, fit_pts plotter.XYs
+    for i := range rs {
+        if !math.IsNaN(preds[i]) {
+            pred_pts = append(pred_pts, plotter.XY{X: rs[i], Y: preds[i]})
+        }
+        if !math.IsNaN(fits[i]) {
+            fit_pts = append(fit_pts, plotter.XY{X: rs[i], Y: fits[i]})
+        }
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Convergence Rate"
+    p.X.Label.Text = "r"
+    p.Y.Label.Text = "rate per iteration"
+    p.Add(plotter.NewGrid())
+
+    l, err := plotter.NewLine(pred_pts)
+    if err != nil {
+        return err
+    }
+    l.Color = Ink
+    p.Add(l)
+    p.Legend.Add("(1/p) ln|(f^p)'(x*)|", l)
+    if len(fit_pts) > 0 {
+        s, err := plotter.NewScatter(fit_pts)
+        if err != nil {
+            return err
+        }
+        s.GlyphStyle.Radius = vg.Points(0.6)
+        s.GlyphStyle.Shape = draw.CircleGlyph{}
+        p.Add(s)
+        p.Legend.Add("fitted ln|Xn - x*|", s)
+    }
+    p.Legend.Top = true
+    p.Legend.Left = true
+
+    p.Add(zero_line())
+    p.X.Min, p.X.Max = r_lo, r_hi
+    p.X.Tick.Marker = FineTicks{}
+    p.Y.Min = math.Max(p.Y.Min, analysis.LiapFloor)
+    // leave room above the peaks at each bifurcation
+    p.Y.Max = math.Max(p.Y.Max, 0) + 0.1*(p.Y.Max-p.Y.Min)
+
+    return p.Save(600, 400, out)
+}
+
+// Separation plots ln|delta_n| for two nearby orbits, with the fit
+// over the growth region when there is one
+func Separation(s analysis.Separation, out string) error {
+    pts := make(plotter.XYs, 0, len(s.Deltas))
+    for i, delta := range s.Deltas {
+        if delta != 0 {
+            pts = append(pts, plotter.XY{X: float64(i), Y: math.Log(delta)})
+        }
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Separation of Nearby Orbits"
+    p.X.Label.Text = "n"
+    p.Y.Label.Text = "ln|delta_n|"
+    p.Add(plotter.NewGrid())
+
+    l, err := plotter.NewLine(pts)
+    if err != nil {
+        return err
+    }
+    l.Color = Ink
+    p.Add(l)
+    p.Legend.Add("ln|Xn - Xn'|", l)
+    if s.Fitted {
+        fit := plotter.NewFunction(func(x float64) float64 { return s.Slope*x + s.Icept })
+        fit.XMin, fit.XMax = 0, float64(s.FitEnd)
+        fit.Dashes = []vg.Length{vg.Points(4), vg.Points(2)}
+        p.Add(fit)
+        p.Legend.Add("fit, lambda = "+strconv.FormatFloat(s.Slope, 'f', 4, 64), fit)
+    }
+    p.Legend.Top = false
+    p.Legend.Left = false
+
+    return p.Save(600, 400, out)
+}
+
+// Cobweb draws the cobweb diagram of f^k at r: the curve
+// y = f^k(x), the diagonal y = x and the staircase
+// x_n -> (x_n, x_n+1) -> (x_n+1, x_n+1) through xs (the orbit of
+// f^k, as from analysis.PowerOrbit) over [x_lo, x_hi]. The first
+// fade steps are drawn lighter so the approach to the attractor
+// stands out from the attractor itself
+func Cobweb(m maps.Map1D, r float64, xs []float64, k, fade int, x_lo, x_hi float64, out string) error {
+    f_k := func(x float64) float64 {
+        for i := 0; i < k; i++ {
+            x = m.F(r, x)
+        }
+        return x
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Cobweb: r=" + strconv.FormatFloat(r, 'f', -1, 64) + " x0=" + strconv.FormatFloat(xs[0], 'f', -1, 64)
+    p.X.Label.Text = "Xn"
+    p.Y.Label.Text = "Xn+1"
+    if k > 1 {
+        p.Title.Text += " (f^" + strconv.Itoa(k) + ")"
+        p.Y.Label.Text = "Xn+" + strconv.Itoa(k)
+    }
+
+    // the map and the diagonal
+    curve := plotter.NewFunction(f_k)
+    curve.Samples = 500 * k
+    curve.Color = color.RGBA{B: 200, A: 255}
+    diag := plotter.NewFunction(func(x float64) float64 { return x })
+    diag.Color = color.Gray{Y: 128}
+    p.Add(plotter.NewGrid(), curve, diag)
+
+    // build the staircase
+    stairs := make(plotter.XYs, 0, 2*len(xs)-1)
+    stairs = append(stairs, plotter.XY{X: xs[0], Y: xs[0]})
+    for i := 1; i < len(xs); i++ {
+        stairs = append(stairs, plotter.XY{X: xs[i-1], Y: xs[i]}, plotter.XY{X: xs[i], Y: xs[i]})
+    }
+
+    // transient steps fade in, the rest is drawn as one line
+    for i := 0; i < fade && 2*i+2 < len(stairs); i++ {
+        l, err := plotter.NewLine(stairs[2*i : 2*i+3])
+        if err != nil {
+            return err
+        }
+        a := 0.15 + 0.6*float64(i)/float64(fade)
+        l.Color = color.NRGBA{R: Ink.R, G: Ink.G, B: Ink.B, A: uint8(255 * a)}
+        p.Add(l)
+    }
+    if 2*fade < len(stairs)-1 {
+        l, err := plotter.NewLine(stairs[2*fade:])
+        if err != nil {
+            return err
+        }
+        l.Color = Ink
+        p.Add(l)
+    }
+
+    name := "f"
+    if k > 1 {
+        name += "^" + strconv.Itoa(k)
+    }
+    p.Legend.Add(name, curve)
+    p.Legend.Add("y = x", diag)
+    p.Legend.Top = true
+    p.Legend.Left = true
+    p.X.Min, p.X.Max = x_lo, x_hi
+    p.Y.Min, p.Y.Max = x_lo, x_hi
+
+    return p.Save(400, 400, out)
+}
+
+// Entropy plots the topological entropy h_top and the Liapunov
+// exponent against r for r in [r_lo, r_hi], both sampled at rs.
+// lambda is cut off below -1 so h_top (at most ln 2) stays readable
+func Entropy(rs, hs, expos []float64, r_lo, r_hi float64, out string) error {
+    h_pts := make(plotter.XYs, len(rs))
+    l_pts := make(plotter.XYs, len(rs))
+    for i := range rs {
+        h_pts[i].X, h_pts[i].Y = rs[i], hs[i]
+        l_pts[i].X, l_pts[i].Y = rs[i], expos[i]
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Topological Entropy and Liapunov Exponent"
+    p.X.Label.Text = "r"
+    p.Add(plotter.NewGrid())
+
+    l, err := plotter.NewLine(l_pts)
+    if err != nil {
+        return err
+    }
+    l.Color = color.Gray{Y: 160}
+    h, err := plotter.NewLine(h_pts)
+    if err != nil {
+        return err
+    }
+    h.Color = Ink
+    h.Width = vg.Points(1.5)
+
+    p.Add(l, h, zero_line())
+    p.Legend.Add("h_top", h)
+    p.Legend.Add("lambda", l)
+    p.Legend.Top = true
+    p.Legend.Left = true
+    p.X.Min, p.X.Max = r_lo, r_hi
+    p.X.Tick.Marker = FineTicks{}
+    p.Y.Min = math.Max(p.Y.Min, -1)
+    p.Y.Max = math.Max(p.Y.Max, math.Ln2)
+
+    return p.Save(600, 400, out)
+}
+
+// SyncSweep plots how far a coupled map lattice is from
+// synchronized (the spatial standard deviation averaged over time)
+// against the coupling strength eps
+func SyncSweep(epss, errs []float64, out string) error {
+    pts := make(plotter.XYs, len(epss))
+    for i := range epss {
+        pts[i].X, pts[i].Y = epss[i], errs[i]
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Synchronization of the Lattice"
+    p.X.Label.Text = "coupling eps"
+    p.Y.Label.Text = "spatial std dev of x"
+    p.Add(plotter.NewGrid())
+
+    l, s, err := plotter.NewLinePoints(pts)
+    if err != nil {
+        return err
+    }
+    l.Color = Ink
+    s.Color = Ink
+    s.Shape = draw.CircleGlyph{}
+    s.Radius = vg.Points(1.5)
+
+    p.Add(l, s)
+    p.Y.Min = 0
+    p.X.Tick.Marker = FineTicks{}
+
+    return p.Save(600, 400, out)
+}
+
+// NoiseShift plots the shift of the onset of chaos against the
+// noise amplitude on log-log axes, with the fitted power law
+// exp(icept) * sigma^slope
+func NoiseShift(sigmas, shifts []float64, slope, icept float64, out string) error {
+    pts := make(plotter.XYs, len(sigmas))
+    for i := range sigmas {
+        pts[i].X, pts[i].Y = sigmas[i], shifts[i]
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Noise-Induced Shift of r_inf"
+    p.X.Label.Text = "noise amplitude"
+    p.Y.Label.Text = "r_inf - r_c"
+    p.X.Scale, p.Y.Scale = plot.LogScale{}, plot.LogScale{}
+    p.X.Tick.Marker, p.Y.Tick.Marker = plot.LogTicks{}, plot.LogTicks{}
+    p.Add(plotter.NewGrid())
+
+    s, err := plotter.NewScatter(pts)
+    if err != nil {
+        return err
+    }
+    s.Color = Ink
+    s.Shape = draw.CircleGlyph{}
+    fit := plotter.NewFunction(func(x float64) float64 { return math.Exp(icept) * math.Pow(x, slope) })
+    fit.Color = color.Gray{Y: 100}
+
+    p.Add(fit, s)
+    p.Legend.Add("r_inf - r_c", s)
+    p.Legend.Add("fit, gamma = "+strconv.FormatFloat(slope, 'f', 3, 64), fit)
+    p.Legend.Top = true
+    p.Legend.Left = true
+
+    return p.Save(600, 400, out)
+}
+
+// Dimensions plots the quantities whose slopes against ln(1/eps)
+// give the box-counting, information and correlation dimensions,
+// each with its fitted line
+func Dimensions(d analysis.Dimensions, out string) error {
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Dimensions of the Attractor"
+    p.X.Label.Text = "ln(1/eps)"
+    p.Y.Label.Text = "ln N, I, -ln C"
+    p.Add(plotter.NewGrid())
+
+    for i, f := range []struct {
+        name string
+        fit  analysis.DimFit
+    }{{"ln N, D0", d.Box}, {"I, D1", d.Info}, {"-ln C, D2", d.Corr}} {
+        fit := f.fit
+        pts := make(plotter.XYs, len(fit.Eps))
+        for k, e := range fit.Eps {
+            pts[k].X, pts[k].Y = -math.Log(e), fit.Ys[k]
+        }
+        s, err := plotter.NewScatter(pts)
+        if err != nil {
+            return err
+        }
+        s.Color = plotutil.Color(i)
+        s.Shape = draw.CircleGlyph{}
+        line := plotter.NewFunction(func(x float64) float64 { return fit.Dim*x + fit.Icept })
+        line.XMin, line.XMax = pts[0].X, pts[len(pts)-1].X
+        line.Color = plotutil.Color(i)
+        line.Dashes = []vg.Length{vg.Points(4), vg.Points(2)}
+        p.Add(s, line)
+        p.Legend.Add(f.name+" = "+strconv.FormatFloat(fit.Dim, 'f', 4, 64)+" +/- "+strconv.FormatFloat(fit.Err, 'f', 4, 64), s, line)
+    }
+    p.Legend.Top = true
+    p.Legend.Left = true
+
+    return p.Save(600, 400, out)
+}
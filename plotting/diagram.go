@@ -0,0 +1,156 @@
+package plotting
+
+import (
+    "image"
+    "image/color"
+    "math"
+    "sort"
+    "strconv"
+
+    "gonum.org/v1/plot"
+    "gonum.org/v1/plot/palette"
+    "gonum.org/v1/plot/palette/moreland"
+    "gonum.org/v1/plot/plotter"
+    "gonum.org/v1/plot/plotutil"
+    "gonum.org/v1/plot/vg"
+    "gonum.org/v1/plot/vg/draw"
+)
+
+// Diagram draws the Feigenbaum diagram as a scatter of the points
+// (rs[i], xs[i]) over [r_lo, r_hi] x [x_lo, x_hi]. When periods is
+// given (one per point) the points are coloured by attractor
+// period, with 0 for chaotic or unresolved
+func Diagram(rs, xs []float64, periods []int, r_lo, r_hi, x_lo, x_hi float64, out string) error {
+    return diagram(rs, xs, periods, 0, "chaotic/unresolved", func(period int) string {
+        return "period " + strconv.Itoa(period)
+    }, r_lo, r_hi, x_lo, x_hi, out)
+}
+
+// Basins draws the Feigenbaum diagram with the points coloured by
+// which of the attractors at their r they belong to (labels as in
+// analysis.BasinMap, -1 for orbits on no attractor)
+func Basins(rs, xs []float64, labels []int, r_lo, r_hi, x_lo, x_hi float64, out string) error {
+    return diagram(rs, xs, labels, -1, "no attractor", func(label int) string {
+        return "attractor " + strconv.Itoa(label+1)
+    }, r_lo, r_hi, x_lo, x_hi, out)
+}
+
+// scatter of the diagram with one colour per class (when classes is
+// given), grey for the class other
+func diagram(rs, xs []float64, classes []int, other int, other_name string, name func(int) string, r_lo, r_hi, x_lo, x_hi float64, out string) error {
+    // group the points by class
+    groups := make(map[int]plotter.XYs)
+    for i := range rs {
+        class := other
+        if classes != nil {
+            class = classes[i]
+        }
+        groups[class] = append(groups[class], plotter.XY{X: rs[i], Y: xs[i]})
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+
+    // do some nice formatting
+    p.Title.Text = "Feigenbaum Diagram"
+    p.X.Label.Text = "r"
+    p.Y.Label.Text = "x"
+    p.Add(plotter.NewGrid())
+
+    // only show the window the grid was built for
+    p.X.Min, p.X.Max = r_lo, r_hi
+    p.Y.Min, p.Y.Max = x_lo, x_hi
+    p.X.Tick.Marker = FineTicks{}
+    p.Y.Tick.Marker = FineTicks{}
+
+    keys := make([]int, 0, len(groups))
+    for class := range groups {
+        keys = append(keys, class)
+    }
+    sort.Ints(keys)
+
+    for i, class := range keys {
+        s, err := plotter.NewScatter(groups[class])
+        if err != nil {
+            return err
+        }
+
+        // scatter plot related formatting
+        s.GlyphStyle.Color = Ink
+        s.GlyphStyle.Radius = vg.Points(0.5)
+        s.GlyphStyle.Shape = draw.CircleGlyph{}
+        p.Add(s)
+
+        // one colour per class, grey for the other points
+        if classes != nil {
+            if class == other {
+                s.GlyphStyle.Color = color.Gray{Y: 100}
+                p.Legend.Add(other_name, s)
+            } else {
+                s.GlyphStyle.Color = plotutil.Color(i)
+                p.Legend.Add(name(class), s)
+            }
+        }
+    }
+
+    return p.Save(600, 400, out)
+}
+
+// Transients draws a heatmap of the iterations every (r, x0) cell
+// takes to get within tol of the attractor (z as from
+// analysis.Grid.Transients, nr x nx cells over [r_lo, r_hi] x
+// [x_lo, x_hi]), coloured by log10(1 + iterations) up to n_max.
+// NaN cells are left grey. The colour scale is drawn on the right
+func Transients(z []float64, nr, nx int, r_lo, r_hi, x_lo, x_hi float64, n_max int, tol float64, out string) error {
+    // colour the cells by hand (gonum's heatmap and colour bar
+    // make 16-bit images, which the pdf backend can't embed)
+    cmap := moreland.ExtendedBlackBody()
+    cmap.SetMin(0)
+    cmap.SetMax(math.Log10(1 + float64(n_max)))
+    heat := image.NewRGBA(image.Rect(0, 0, nr, nx))
+    for i, v := range z {
+        ir, ix := i/nx, i%nx
+        heat.Set(ir, nx-1-ix, heat_color(cmap, math.Log10(1+v)))
+    }
+    scale := image.NewRGBA(image.Rect(0, 0, 1, 256))
+    for i := 0; i < 256; i++ {
+        scale.Set(0, 255-i, heat_color(cmap, cmap.Max()*float64(i)/255))
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Iterations to within " + strconv.FormatFloat(tol, 'g', -1, 64) + " of the attractor (grey: no cycle)"
+    p.X.Label.Text = "r"
+    p.Y.Label.Text = "x0"
+    p.Add(plotter.NewImage(heat, r_lo, x_lo, r_hi, x_hi))
+    p.X.Min, p.X.Max = r_lo, r_hi
+    p.Y.Min, p.Y.Max = x_lo, x_hi
+    p.X.Tick.Marker = FineTicks{}
+
+    // the colour scale gets a narrow plot of its own on the right
+    bar, err := plot.New()
+    if err != nil {
+        return err
+    }
+    bar.HideX()
+    bar.Y.Label.Text = "log10(1 + iterations)"
+    bar.Add(plotter.NewImage(scale, 0, 0, 1, cmap.Max()))
+
+    return SaveTiled(out, 800, 500, 0.88, p, bar)
+}
+
+// colour for v on cmap, grey where there's no value
+func heat_color(cmap palette.ColorMap, v float64) color.Color {
+    if math.IsNaN(v) {
+        return color.Gray{Y: 200}
+    }
+    c, err := cmap.At(math.Min(math.Max(v, cmap.Min()), cmap.Max()))
+    if err != nil {
+        return color.Gray{Y: 200}
+    }
+    return c
+}
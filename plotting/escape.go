@@ -0,0 +1,43 @@
+package plotting
+
+import (
+    "image"
+    "image/color"
+    "image/draw"
+    "math"
+)
+
+// EscapeMap turns escape times (as from analysis.Mandelbrot or
+// analysis.Julia) into an image: points that never escaped are
+// Ink, the rest fade from a light tint of it (slow to escape) to
+// white (quick), with log scaling raised to gamma (so larger
+// gammas leave more of the outside white)
+func EscapeMap(times []float64, width, height, n_max int, gamma float64) *image.RGBA {
+    img := image.NewRGBA(image.Rect(0, 0, width, height))
+    for i, t := range times {
+        if t < 0 {
+            img.Set(i%width, i/width, Ink)
+            continue
+        }
+        v := math.Pow(math.Log1p(math.Max(t, 0))/math.Log1p(float64(n_max)), gamma)
+        img.Set(i%width, i/width, blend(0.8*math.Min(v, 1)))
+    }
+    return img
+}
+
+// Stack puts top above bottom, with a grey line gap pixels high
+// between them (the result is as wide as the wider of the two)
+func Stack(top, bottom image.Image, gap int) *image.RGBA {
+    tb, bb := top.Bounds(), bottom.Bounds()
+    width := tb.Dx()
+    if bb.Dx() > width {
+        width = bb.Dx()
+    }
+    img := image.NewRGBA(image.Rect(0, 0, width, tb.Dy()+gap+bb.Dy()))
+    draw.Draw(img, img.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+    draw.Draw(img, image.Rect(0, 0, tb.Dx(), tb.Dy()), top, tb.Min, draw.Src)
+    line := image.Rect(0, tb.Dy(), width, tb.Dy()+gap)
+    draw.Draw(img, line, &image.Uniform{C: color.Gray{Y: 128}}, image.Point{}, draw.Src)
+    draw.Draw(img, image.Rect(0, tb.Dy()+gap, bb.Dx(), tb.Dy()+gap+bb.Dy()), bottom, bb.Min, draw.Src)
+    return img
+}
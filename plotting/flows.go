@@ -0,0 +1,135 @@
+package plotting
+
+import (
+    "image/color"
+    "strconv"
+
+    "gonum.org/v1/plot"
+    "gonum.org/v1/plot/plotter"
+    "gonum.org/v1/plot/vg"
+    "gonum.org/v1/plot/vg/draw"
+
+    "github.com/tmitchel/chaos/flows"
+)
+
+// Rossler overlays the three projections x-y, x-z and y-z of a
+// Rossler trajectory started at pts[0]
+func Rossler(r flows.Rossler, pts []flows.Point, out string) error {
+    pts_xy := make(plotter.XYs, len(pts))
+    pts_xz := make(plotter.XYs, len(pts))
+    pts_yz := make(plotter.XYs, len(pts))
+    for i, pt := range pts {
+        pts_xy[i].X, pts_xy[i].Y = pt.X, pt.Y
+        pts_xz[i].X, pts_xz[i].Y = pt.X, pt.Z
+        pts_yz[i].X, pts_yz[i].Y = pt.Y, pt.Z
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+
+    // make the plots pretty
+    lxy, err := plotter.NewLine(pts_xy)
+    if err != nil {
+        return err
+    }
+    lxy.Color = color.RGBA{G: 255}
+    lxz, err := plotter.NewLine(pts_xz)
+    if err != nil {
+        return err
+    }
+    lxz.Color = color.RGBA{R: 255}
+    lyz, err := plotter.NewLine(pts_yz)
+    if err != nil {
+        return err
+    }
+    lyz.Color = color.RGBA{B: 255}
+    p.Add(lxy, lxz, lyz)
+
+    // convert values to strings for the title
+    a_val := strconv.FormatFloat(r.A, 'f', -1, 64)
+    b_val := strconv.FormatFloat(r.B, 'f', -1, 64)
+    c_val := strconv.FormatFloat(r.C, 'f', -1, 64)
+    x0_val := strconv.FormatFloat(pts[0].X, 'f', -1, 64)
+    y0_val := strconv.FormatFloat(pts[0].Y, 'f', -1, 64)
+    z0_val := strconv.FormatFloat(pts[0].Z, 'f', -1, 64)
+
+    p.Title.Text = "Rossler System:\n\t a=" + a_val + " b=" + b_val + " c=" + c_val + "\n\t x0=" + x0_val + " y0=" + y0_val + " z0=" + z0_val
+    p.Legend.Add("X(t) vs Y(T)", lxy)
+    p.Legend.Add("X(t) vs Z(T)", lxz)
+    p.Legend.Add("Y(t) vs Z(T)", lyz)
+
+    return p.Save(600, 400, out)
+}
+
+// Duffing plots y = dx/dt against x for one or more Duffing
+// trajectories, one per force (with a legend when there's more
+// than one)
+func Duffing(forces []float64, trajs [][]flows.Point, out string) error {
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+
+    colors := []color.Color{color.RGBA{R: 255}, color.RGBA{B: 255}}
+    title := "Poincare Section F="
+    for i, traj := range trajs {
+        pts := make(plotter.XYs, len(traj))
+        for j, pt := range traj {
+            pts[j].X, pts[j].Y = pt.X, pt.Y
+        }
+        l, err := plotter.NewLine(pts)
+        if err != nil {
+            return err
+        }
+        F_val := strconv.FormatFloat(forces[i], 'f', -1, 64)
+        if len(trajs) > 1 {
+            l.Color = colors[i%len(colors)]
+            p.Legend.Add("F="+F_val, l)
+        }
+        if i > 0 {
+            title += " vs F="
+        }
+        title += F_val
+        p.Add(l)
+    }
+
+    p.X.Label.Text = "X"
+    p.Y.Label.Text = "Y=dx/dt"
+    p.Title.Text = title
+
+    return p.Save(600, 400, out)
+}
+
+// ReturnMap plots each value in xs against the next, e.g. the x
+// coordinate at successive crossings of a Poincare section, along
+// with the diagonal
+func ReturnMap(xs []float64, title, label string, out string) error {
+    pts := make(plotter.XYs, 0, len(xs))
+    for i := 1; i < len(xs); i++ {
+        pts = append(pts, plotter.XY{X: xs[i-1], Y: xs[i]})
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = title
+    p.X.Label.Text = label + "_n"
+    p.Y.Label.Text = label + "_n+1"
+    p.Add(plotter.NewGrid())
+
+    s, err := plotter.NewScatter(pts)
+    if err != nil {
+        return err
+    }
+    s.GlyphStyle.Color = Ink
+    s.GlyphStyle.Radius = vg.Points(1)
+    s.GlyphStyle.Shape = draw.CircleGlyph{}
+    diag := plotter.NewFunction(func(x float64) float64 { return x })
+    diag.Color = color.Gray{Y: 128}
+    p.Add(s, diag)
+
+    return p.Save(400, 400, out)
+}
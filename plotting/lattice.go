@@ -0,0 +1,27 @@
+package plotting
+
+import (
+    "image"
+    "math"
+)
+
+// SpaceTime draws a space-time pattern (one row of sites per time
+// step, as from analysis.Lattice.Run) with time going down, shading
+// each site from white at x_lo to Ink at x_hi, scale pixels square
+func SpaceTime(frames [][]float64, x_lo, x_hi float64, scale int) *image.RGBA {
+    if len(frames) == 0 {
+        return image.NewRGBA(image.Rect(0, 0, 0, 0))
+    }
+    img := image.NewRGBA(image.Rect(0, 0, len(frames[0])*scale, len(frames)*scale))
+    for t, row := range frames {
+        for i, x := range row {
+            c := blend(math.Max(0, math.Min(1, (x-x_lo)/(x_hi-x_lo))))
+            for dy := 0; dy < scale; dy++ {
+                for dx := 0; dx < scale; dx++ {
+                    img.Set(i*scale+dx, t*scale+dy, c)
+                }
+            }
+        }
+    }
+    return img
+}
@@ -0,0 +1,120 @@
+////////////////////////////////////////////////////////
+// Purpose: Plots and rasters of the results from the //
+// maps, flows and analysis packages                  //
+////////////////////////////////////////////////////////
+package plotting
+
+import (
+    "image"
+    "image/color"
+    "image/png"
+    "math"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+
+    "gonum.org/v1/plot"
+    "gonum.org/v1/plot/vg"
+    "gonum.org/v1/plot/vg/draw"
+)
+
+// Ink is the colour the diagrams are drawn in
+var Ink = color.RGBA{R: 128, G: 0, B: 0, A: 255}
+
+// FineTicks are gonum's default tick marks, labelled with enough
+// digits to tell the ticks of a narrow window apart
+type FineTicks struct{}
+
+func (FineTicks) Ticks(min, max float64) []plot.Tick {
+    ticks := plot.DefaultTicks{}.Ticks(min, max)
+    for i := range ticks {
+        if ticks[i].Label != "" {
+            ticks[i].Label = strconv.FormatFloat(ticks[i].Value, 'g', 8, 64)
+        }
+    }
+    return ticks
+}
+
+// ToneMap turns a width x height histogram (as from
+// analysis.Density) into an image, mapping counts with
+// log(1+c)/log(1+max) and then gamma and blending Ink over white
+func ToneMap(counts []float64, width, height int, gamma float64) *image.RGBA {
+    max_count := 0.
+    for _, c := range counts {
+        max_count = math.Max(max_count, c)
+    }
+    img := image.NewRGBA(image.Rect(0, 0, width, height))
+    for i, c := range counts {
+        v := 0.
+        if max_count > 0 {
+            v = math.Pow(math.Log1p(c)/math.Log1p(max_count), 1/gamma)
+        }
+        img.Set(i%width, i/width, blend(v))
+    }
+    return img
+}
+
+// mix white and ink, v = 0 is white and v = 1 is pure ink
+func blend(v float64) color.RGBA {
+    mix := func(c uint8) uint8 {
+        return uint8(255 - v*(255-float64(c)) + 0.5)
+    }
+    return color.RGBA{R: mix(Ink.R), G: mix(Ink.G), B: mix(Ink.B), A: 255}
+}
+
+// FlipVertical turns an image upside down in place
+func FlipVertical(img *image.RGBA) {
+    b := img.Bounds()
+    for y := b.Min.Y; y < b.Min.Y+b.Dy()/2; y++ {
+        y2 := b.Max.Y - 1 - (y - b.Min.Y)
+        for x := b.Min.X; x < b.Max.X; x++ {
+            top, bottom := img.At(x, y), img.At(x, y2)
+            img.Set(x, y, bottom)
+            img.Set(x, y2, top)
+        }
+    }
+}
+
+// SavePNG writes an image out as a png
+func SavePNG(img image.Image, out string) error {
+    f, err := os.Create(out)
+    if err != nil {
+        return err
+    }
+    if err := png.Encode(f, img); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
+
+// Numbered inserts a frame number before the extension:
+// zoom.png -> zoom_3.png
+func Numbered(out string, n int) string {
+    ext := filepath.Ext(out)
+    return strings.TrimSuffix(out, ext) + "_" + strconv.Itoa(n) + ext
+}
+
+// SaveTiled saves two plots side by side, the first taking frac of
+// the width (the format follows out's extension)
+func SaveTiled(out string, width, height vg.Length, frac float64, left, right *plot.Plot) error {
+    c, err := draw.NewFormattedCanvas(width, height, strings.TrimPrefix(filepath.Ext(out), "."))
+    if err != nil {
+        return err
+    }
+    dc := draw.New(c)
+    split := width * vg.Length(frac)
+    left.Draw(draw.Crop(dc, 0, split-width, 0, 0))
+    right.Draw(draw.Crop(dc, split, 0, 0, 0))
+
+    f, err := os.Create(out)
+    if err != nil {
+        return err
+    }
+    if _, err := c.WriteTo(f); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
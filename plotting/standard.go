@@ -0,0 +1,110 @@
+package plotting
+
+import (
+    "image"
+    "image/color"
+    "image/draw"
+    "math"
+
+    "gonum.org/v1/plot"
+    "gonum.org/v1/plot/plotter"
+    "gonum.org/v1/plot/vg"
+
+    "github.com/tmitchel/chaos/analysis"
+    "github.com/tmitchel/chaos/maps"
+)
+
+// Portrait draws orbits of the standard map on a width x height
+// image of the torus (theta across, p up): the chaotic sea in grey
+// underneath and every regular orbit (KAM torus or island chain)
+// in a colour of its own on top
+func Portrait(orbits []analysis.TorusOrbit, width, height int) *image.RGBA {
+    img := image.NewRGBA(image.Rect(0, 0, width, height))
+    draw.Draw(img, img.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+    paint := func(o analysis.TorusOrbit, c color.Color) {
+        for i := range o.Thetas {
+            col := int(o.Thetas[i] / (2 * math.Pi) * float64(width))
+            row := int((math.Pi - o.Ps[i]) / (2 * math.Pi) * float64(height))
+            if col >= 0 && col < width && row >= 0 && row < height {
+                img.Set(col, row, c)
+            }
+        }
+    }
+    for _, o := range orbits {
+        if o.Chaotic {
+            paint(o, color.Gray{Y: 170})
+        }
+    }
+    n := 0
+    for _, o := range orbits {
+        if !o.Chaotic {
+            // spread the hues by the golden angle so neighbours differ
+            paint(o, hue(math.Mod(float64(n)*0.618033988749895, 1)))
+            n++
+        }
+    }
+    return img
+}
+
+// a fully saturated, fairly dark colour of hue h in [0, 1)
+func hue(h float64) color.RGBA {
+    const v, s = 0.8, 0.9
+    i := math.Floor(h * 6)
+    f := h*6 - i
+    p, q, t := v*(1-s), v*(1-f*s), v*(1-(1-f)*s)
+    var r, g, b float64
+    switch int(i) % 6 {
+    case 0:
+        r, g, b = v, t, p
+    case 1:
+        r, g, b = q, v, p
+    case 2:
+        r, g, b = p, v, t
+    case 3:
+        r, g, b = p, q, v
+    case 4:
+        r, g, b = t, p, v
+    default:
+        r, g, b = v, p, q
+    }
+    return color.RGBA{R: uint8(255 * r), G: uint8(255 * g), B: uint8(255 * b), A: 255}
+}
+
+// SeaFraction plots the chaotic share of the standard map's torus
+// against K, with Greene's K for the last KAM torus marked
+func SeaFraction(ks, fracs []float64, out string) error {
+    pts := make(plotter.XYs, len(ks))
+    for i := range ks {
+        pts[i].X, pts[i].Y = ks[i], fracs[i]
+    }
+
+    p, err := plot.New()
+    if err != nil {
+        return err
+    }
+    p.Title.Text = "Chaotic Sea of the Standard Map"
+    p.X.Label.Text = "K"
+    p.Y.Label.Text = "fraction of the torus"
+    p.Add(plotter.NewGrid())
+
+    l, err := plotter.NewLine(pts)
+    if err != nil {
+        return err
+    }
+    l.Color = Ink
+    kc, err := plotter.NewLine(plotter.XYs{{X: maps.StandardKC, Y: 0}, {X: maps.StandardKC, Y: 1}})
+    if err != nil {
+        return err
+    }
+    kc.Color = color.Gray{Y: 128}
+    kc.Dashes = []vg.Length{vg.Points(4), vg.Points(4)}
+
+    p.Add(l, kc)
+    p.Legend.Add("chaotic fraction", l)
+    p.Legend.Add("K_c (Greene)", kc)
+    p.Legend.Top = true
+    p.Legend.Left = true
+    p.Y.Min, p.Y.Max = 0, 1
+
+    return p.Save(600, 400, out)
+}